@@ -1,29 +1,270 @@
 package backoff
 
 import (
-	"math/rand"
+	"context"
+	crand "crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"iter"
+	"math"
+	"math/rand/v2"
+	"slices"
+	"strconv"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
 // Backoff encapsulates the state for exponential backoff.
+//
+// O valor zero está pronto para uso, com initial 100ms, fator 2.0, max 30s e
+// FullJitter, o que permite declarar var b backoff.Backoff ou embuti-lo em
+// outra struct sem chamar New.
+//
+// Um Backoff não deve ser copiado por valor: a cópia compartilharia o estado
+// de forma inconsistente e teria seu próprio mutex. Use sempre *Backoff e
+// Clone para obter uma instância independente; go vet (copylocks) acusa cópias
+// acidentais.
 type Backoff struct {
-	mu          sync.Mutex    // garante segurança em concorrência
-	initial     time.Duration // valor base
-	factor      float64       // fator ≥ 1.0
-	max         time.Duration // limite superior
-	withJitter  bool          // habilita jitter
-	current     time.Duration // último intervalo retornado
-	initialized bool          // indica primeira chamada
+	mu sync.Mutex // garante segurança em concorrência
+	config
+
+	last    time.Duration             // último intervalo sorteado
+	cur     atomic.Int64              // último intervalo base, codificado por encodeCur
+	attempt atomic.Int64              // chamadas a Next desde o último Reset
+	rnd     *rand.Rand                // fonte aleatória própria (nil = global)
+	start   atomic.Pointer[time.Time] // primeira chamada a Next desde o último Reset
+	total   time.Duration             // soma dos intervalos retornados desde o último Reset
+	scale   atomic.Uint64             // fator de Scale em bits de float64 (0 = 1.0)
+	maxed   atomic.Bool               // WithOnMax já disparou desde o último Reset
+	hint    atomic.Pointer[floorHint] // piso sugerido por SetFloorHint
+	lazy    sync.Once                 // aplica os padrões ao valor zero
+	stagger atomic.Int64              // espalhamento pendente de ResetJittered
+	primed  bool                      // a última chamada usou WithFirstDelay ou WithImmediateFirst; exige b.mu
+	factorR atomic.Uint64             // fator de Reconfigure em bits de float64 (0 = config)
+	maxR    atomic.Int64              // max de Reconfigure, codificado por encodeCur (0 = config)
+	flight  atomic.Pointer[flight]    // laço de retry de DoOnce em andamento
+	chosen  int                       // índice+1 da escolha de Weighted da próxima chamada (0 = não sorteada); exige b.mu
+}
+
+// floorHint é um piso temporário registrado por SetFloorHint.
+type floorHint struct {
+	d     time.Duration
+	until time.Time
+}
+
+// encodeCur inverte o bit de sinal do intervalo base, de modo que o valor zero
+// de Backoff.cur signifique "nenhuma chamada a Next" e intervalo e
+// inicialização mudem juntos em um único compare-and-swap.
+func encodeCur(d time.Duration) int64 {
+	return int64(d) ^ math.MinInt64
+}
+
+// decodeCur desfaz encodeCur; ok é false antes da primeira chamada a Next.
+func decodeCur(v int64) (d time.Duration, ok bool) {
+	if v == 0 {
+		return 0, false
+	}
+	return time.Duration(v ^ math.MinInt64), true
+}
+
+// config agrupa os parâmetros imutáveis após a construção, copiados por Clone.
+type config struct {
+	initial     time.Duration  // valor base
+	factor      float64        // fator ≥ 1.0 (< 1.0 com WithFloor); máximo do fator sorteado
+	factorMin   float64        // limite inferior do fator sorteado
+	randFactor  bool           // sorteia o fator a cada chamada a Next
+	max         time.Duration  // limite superior (0 = ilimitado)
+	strategy    JitterStrategy // estratégia de jitter
+	maxAttempts int            // limite de tentativas (0 = ilimitado)
+	maxElapsed  time.Duration  // limite de tempo total (0 = ilimitado)
+	maxTotal    time.Duration  // limite da soma dos intervalos (0 = ilimitado)
+	minDelay    time.Duration  // piso aplicado após o jitter
+	decayFloor  time.Duration  // piso do intervalo base; permite fator < 1.0
+	spread      time.Duration  // deslocamento máximo da primeira chamada
+	jitterFrac  float64        // fração f de ProportionalJitter, em [0, 1]
+	decrease    float64        // fator de Success, em (0, 1)
+	step        time.Duration  // incremento do modo linear (0 = exponencial)
+	clock       Clock          // fonte de tempo (nil = relógio real)
+	growth      GrowthFunc     // crescimento personalizado (nil = padrão)
+	crypto      bool           // jitter sorteado com crypto/rand
+	strictMax   bool           // limita o intervalo final a max
+	firstFloor  bool           // primeira tentativa com jitter completo em [base/2, base]
+	window      time.Duration  // desvio absoluto de WithJitterWindow (0 = desligado)
+	softCap     time.Duration  // início da suavização de WithSoftCap (0 = desligado)
+	firstDelay  time.Duration  // intervalo base da primeira chamada (0 = initial)
+	immediate   bool           // primeira chamada retorna zero
+	feedback    bool           // cresce a partir do último intervalo sorteado
+	rounding    time.Duration  // granularidade de WithRounding (0 = desligado)
+
+	ctx      context.Context                        // encerra NextOK ao terminar (nil = nenhum)
+	observer func(attempt int, delay time.Duration) // chamado após cada intervalo
+	onMax    func()                                 // chamado ao saturar em max
+	name     string                                 // rótulo para logs e métricas
+	retryIf  func(err error) bool                   // classifica erros em Retry e Do (nil = todos)
+	budget   *Budget                                // orçamento compartilhado de NextOK (nil = nenhum)
+
+	choices    []time.Duration // intervalos de Weighted (nil = crescimento normal)
+	cumWeights []float64       // pesos acumulados de choices
+
+	named func(name string, attempt int, delay time.Duration) // observador de WithNamedObserver
 }
 
-// New cria um Backoff com jitter opcional (default true).
+// New cria um Backoff com jitter opcional (default true). max igual a zero
+// significa sem limite superior: o intervalo cresce até o maior Duration
+// representável, sem estourar. Parâmetros inválidos são ajustados: fatores
+// menores que 1.0, NaN ou infinitos viram 1.0 e a fração de jitter é limitada
+// a [0, 1]. Use NewValidated para rejeitá-los.
 func New(initial time.Duration, factor float64, max time.Duration, opts ...Option) *Backoff {
+	b := build(initial, factor, max, opts)
+	b.normalize()
+	return b
+}
+
+// Constant cria um Backoff que sempre retorna interval (com jitter, se
+// habilitado), equivalente a New(interval, 1.0, interval, opts...).
+func Constant(interval time.Duration, opts ...Option) *Backoff {
+	return New(interval, 1.0, interval, opts...)
+}
+
+// Linear cria um Backoff cujo intervalo cresce somando step a cada chamada:
+// initial, initial+step, initial+2*step, ... até max.
+func Linear(initial, step, max time.Duration, opts ...Option) *Backoff {
+	return New(initial, 1.0, max, append([]Option{WithLinearStep(step)}, opts...)...)
+}
+
+// NewWith cria um Backoff apenas com opções, partindo de initial 100ms,
+// factor 2.0 e max 10s.
+func NewWith(opts ...Option) *Backoff {
+	return New(100*time.Millisecond, 2.0, 10*time.Second, opts...)
+}
+
+// Default cria um Backoff recomendado para uso geral: initial 100ms,
+// factor 2.0, max 30s, FullJitter e tempo total limitado a 5 minutos
+// (WithMaxElapsedTime). opts são aplicadas em seguida.
+func Default(opts ...Option) *Backoff {
+	return New(100*time.Millisecond, 2.0, 30*time.Second,
+		append([]Option{WithMaxElapsedTime(5 * time.Minute)}, opts...)...)
+}
+
+// GoogleCloud cria um Backoff com o backoff exponencial truncado das
+// bibliotecas cliente do Google Cloud: initial 1s, max 32s e
+// WithTruncatedExponential, isto é, random(0, min(32s, 1s*2^n)) na tentativa
+// n (0, 1, 2, ...). opts são aplicadas em seguida.
+func GoogleCloud(opts ...Option) *Backoff {
+	return New(1*time.Second, 2.0, 32*time.Second,
+		append([]Option{WithTruncatedExponential()}, opts...)...)
+}
+
+// NewValidated funciona como New, mas retorna erro se initial <= 0,
+// factor < 1.0 (ou <= 0 com WithFloor), factor for NaN ou infinito,
+// max < initial (exceto max zero, sem limite) ou a fração de WithJitterFactor
+// estiver fora de [0, 1].
+func NewValidated(initial time.Duration, factor float64, max time.Duration, opts ...Option) (*Backoff, error) {
+	b := build(initial, factor, max, opts)
+	if err := b.config.validate(); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// Weighted cria um Backoff cujo Next sorteia, a cada chamada, um dos
+// intervalos de choices com probabilidade proporcional ao peso correspondente
+// em weights, em vez de crescer exponencialmente. Por padrão não há jitter
+// sobre o intervalo sorteado; initial e max passam a ser o menor e o maior
+// intervalo. Entra em pânico se os slices estiverem vazios ou tiverem
+// tamanhos diferentes, se algum intervalo for negativo ou se algum peso não
+// for positivo e finito.
+func Weighted(choices []time.Duration, weights []float64, opts ...Option) *Backoff {
+	if len(choices) == 0 || len(choices) != len(weights) {
+		panic(fmt.Sprintf("backoff: Weighted needs one weight per choice, got %d choices and %d weights", len(choices), len(weights)))
+	}
+	cum := make([]float64, len(weights))
+	var sum float64
+	for i, w := range weights {
+		if math.IsNaN(w) || math.IsInf(w, 0) || w <= 0 {
+			panic(fmt.Sprintf("backoff: Weighted weights must be positive and finite, got %v", w))
+		}
+		if choices[i] < 0 {
+			panic(fmt.Sprintf("backoff: Weighted choices must not be negative, got %v", choices[i]))
+		}
+		sum += w
+		cum[i] = sum
+	}
+
+	b := New(slices.Min(choices), 1.0, slices.Max(choices), append([]Option{WithJitter(false)}, opts...)...)
+	b.choices = slices.Clone(choices)
+	b.cumWeights = cum
+	return b
+}
+
+// pick sorteia um dos intervalos de Weighted segundo os pesos; exige b.mu
+// travado.
+func (b *Backoff) pick() time.Duration {
+	return b.scaled(b.choices[b.pickIndex()])
+}
+
+// pickIndex sorteia o índice de um dos intervalos de Weighted segundo os
+// pesos; exige b.mu travado.
+func (b *Backoff) pickIndex() int {
+	r := b.float64() * b.cumWeights[len(b.cumWeights)-1]
+	i, _ := slices.BinarySearch(b.cumWeights, r)
+	// r == soma acumulada cai no intervalo seguinte
+	if i < len(b.cumWeights) && b.cumWeights[i] == r {
+		i++
+	}
+	return min(i, len(b.choices)-1)
+}
+
+// choice retorna o intervalo de Weighted da próxima chamada a Next. O sorteio
+// acontece uma vez por passo e é guardado até o estado avançar, de modo que
+// Peek, Drain e o próprio avanço veem o mesmo valor; exige b.mu travado.
+func (b *Backoff) choice() time.Duration {
+	if b.chosen == 0 {
+		b.chosen = b.pickIndex() + 1
+	}
+	return b.scaled(b.choices[b.chosen-1])
+}
+
+// MustNew funciona como NewValidated, mas entra em pânico com o erro de
+// validação em vez de retorná-lo. Feita para inicializar variáveis de pacote,
+// falhando logo na carga do programa diante de uma configuração inválida:
+//
+//	var reconnect = backoff.MustNew(time.Second, 2.0, time.Minute)
+func MustNew(initial time.Duration, factor float64, max time.Duration, opts ...Option) *Backoff {
+	b, err := NewValidated(initial, factor, max, opts...)
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
+
+// setup torna o valor zero de Backoff utilizável: na primeira chamada, se o
+// Backoff não veio de um construtor (fator zero), aplica initial 100ms, fator
+// 2.0, max 30s e FullJitter, como Default mas sem limite de tempo total. Os
+// métodos que leem a configuração a chamam antes de tudo.
+func (b *Backoff) setup() {
+	b.lazy.Do(func() {
+		if b.factor == 0 {
+			b.initial = 100 * time.Millisecond
+			b.factor = 2.0
+			b.max = 30 * time.Second
+			b.strategy = FullJitter
+		}
+	})
+}
+
+// build cria o Backoff e aplica as opções, sem validar.
+func build(initial time.Duration, factor float64, max time.Duration, opts []Option) *Backoff {
 	b := &Backoff{
-		initial:    initial,
-		factor:     factor,
-		max:        max,
-		withJitter: true,
+		config: config{
+			initial:  initial,
+			factor:   factor,
+			max:      max,
+			strategy: FullJitter,
+		},
+		rnd: newRand(),
 	}
 	for _, opt := range opts {
 		opt(b)
@@ -31,46 +272,1785 @@ func New(initial time.Duration, factor float64, max time.Duration, opts ...Optio
 	return b
 }
 
+// normalize ajusta parâmetros inválidos para valores seguros.
+func (b *Backoff) normalize() {
+	if b.config.checkFactor() != nil {
+		b.factor = 1.0
+	}
+	if b.randFactor {
+		if validateFactor(b.factorMin) != nil {
+			b.factorMin = 1.0
+		}
+		b.factor = max(b.factor, b.factorMin)
+	}
+	switch {
+	case math.IsNaN(b.jitterFrac) || b.jitterFrac < 0:
+		b.jitterFrac = 0
+	case b.jitterFrac > 1:
+		b.jitterFrac = 1
+	}
+	b.window = max(b.window, 0)
+	if b.softCap < 0 || b.max == 0 || b.softCap >= b.max {
+		b.softCap = 0
+	}
+}
+
+// validate verifica os parâmetros de construção.
+func (c config) validate() error {
+	if c.initial <= 0 {
+		return fmt.Errorf("backoff: initial must be positive, got %v", c.initial)
+	}
+	if err := c.checkFactor(); err != nil {
+		return err
+	}
+	if c.randFactor {
+		if err := validateFactor(c.factorMin); err != nil {
+			return err
+		}
+		if c.factor < c.factorMin {
+			return fmt.Errorf("backoff: max factor (%v) must not be less than min factor (%v)", c.factor, c.factorMin)
+		}
+	}
+	if c.max != 0 && c.max < c.initial {
+		return fmt.Errorf("backoff: max (%v) must not be less than initial (%v)", c.max, c.initial)
+	}
+	if math.IsNaN(c.jitterFrac) || c.jitterFrac < 0 || c.jitterFrac > 1 {
+		return fmt.Errorf("backoff: jitter factor must be in [0, 1], got %v", c.jitterFrac)
+	}
+	if c.window < 0 {
+		return fmt.Errorf("backoff: jitter window must not be negative, got %v", c.window)
+	}
+	if c.softCap < 0 || (c.softCap > 0 && (c.max == 0 || c.softCap >= c.max)) {
+		return fmt.Errorf("backoff: soft cap (%v) must be positive and less than a nonzero max (%v)", c.softCap, c.max)
+	}
+	return nil
+}
+
+// checkFactor verifica o fator: ≥ 1.0, ou positivo com WithFloor.
+func (c config) checkFactor() error {
+	if c.decayFloor <= 0 {
+		return validateFactor(c.factor)
+	}
+	if math.IsNaN(c.factor) || math.IsInf(c.factor, 0) || c.factor <= 0 {
+		return fmt.Errorf("backoff: factor must be positive and finite, got %v", c.factor)
+	}
+	return nil
+}
+
+// scaleFactor retorna o fator aplicado por Scale, ou 1.0 sem escala.
+func (b *Backoff) scaleFactor() float64 {
+	if v := b.scale.Load(); v != 0 {
+		return math.Float64frombits(v)
+	}
+	return 1
+}
+
+// scaled multiplica d pelo fator de Scale, saturando no maior Duration.
+func (b *Backoff) scaled(d time.Duration) time.Duration {
+	f := b.scaleFactor()
+	if f == 1 {
+		return d
+	}
+	return scaleBy(d, f)
+}
+
+// scaleBy multiplica d por f, saturando no maior Duration.
+func scaleBy(d time.Duration, f float64) time.Duration {
+	v := float64(d) * f
+	if v >= math.MaxInt64 {
+		return math.MaxInt64
+	}
+	return time.Duration(v)
+}
+
+// initialDelay retorna o intervalo inicial com a escala de Scale aplicada.
+func (b *Backoff) initialDelay() time.Duration {
+	return b.scaled(b.initial)
+}
+
+// maxDelay retorna o limite superior efetivo com a escala de Scale aplicada:
+// max escalado, ou o maior Duration quando max é zero.
+func (b *Backoff) maxDelay() time.Duration {
+	m := b.maxValue()
+	if m == 0 {
+		return math.MaxInt64
+	}
+	return b.scaled(m)
+}
+
+// maxValue retorna max, ou o valor definido por Reconfigure.
+func (b *Backoff) maxValue() time.Duration {
+	if m, ok := decodeCur(b.maxR.Load()); ok {
+		return m
+	}
+	return b.max
+}
+
+// growthFactor retorna o fator, ou o valor definido por Reconfigure.
+func (b *Backoff) growthFactor() float64 {
+	if v := b.factorR.Load(); v != 0 {
+		return math.Float64frombits(v)
+	}
+	return b.factor
+}
+
+// Reconfigure troca o fator e o limite superior em tempo de execução, por
+// exemplo a pedido de um plano de controle, sem mexer na posição atual da
+// progressão: o próximo Next cresce a partir do intervalo atual com os novos
+// valores. Os parâmetros passam pelas mesmas regras de NewValidated; se forem
+// inválidos, retorna erro e nada muda. Config e String passam a reportar os
+// novos valores e Clone os copia.
+func (b *Backoff) Reconfigure(factor float64, max time.Duration) error {
+	b.setup()
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	c := b.config
+	c.factor = factor
+	if err := c.checkFactor(); err != nil {
+		return err
+	}
+	if c.randFactor && factor < c.factorMin {
+		return fmt.Errorf("backoff: max factor (%v) must not be less than min factor (%v)", factor, c.factorMin)
+	}
+	if max < 0 || (max != 0 && max < c.initial) {
+		return fmt.Errorf("backoff: max (%v) must be zero or not less than initial (%v)", max, c.initial)
+	}
+	b.factorR.Store(math.Float64bits(factor))
+	b.maxR.Store(encodeCur(max))
+	return nil
+}
+
+// validateFactor verifica se o fator é finito e ≥ 1.0.
+func validateFactor(factor float64) error {
+	if math.IsNaN(factor) || math.IsInf(factor, 0) {
+		return fmt.Errorf("backoff: factor must be finite, got %v", factor)
+	}
+	if factor < 1.0 {
+		return fmt.Errorf("backoff: factor must be at least 1.0, got %v", factor)
+	}
+	return nil
+}
+
 // Option permite customizar Backoff.
 type Option func(*Backoff)
 
-// WithJitter desabilita ou habilita o jitter.
+// WithInitial define o intervalo inicial.
+func WithInitial(d time.Duration) Option {
+	return func(b *Backoff) {
+		b.initial = d
+	}
+}
+
+// WithFactor define o fator de crescimento.
+func WithFactor(f float64) Option {
+	return func(b *Backoff) {
+		b.factor = f
+	}
+}
+
+// WithFloor define o piso do intervalo base e permite fatores entre 0 e 1.0:
+// o intervalo decai de initial até d, por exemplo para sondar a recuperação
+// de um serviço, e para no piso. Sem WithFloor, fatores menores que 1.0
+// continuam inválidos.
+func WithFloor(d time.Duration) Option {
+	return func(b *Backoff) {
+		b.decayFloor = d
+	}
+}
+
+// WithMultiplier é um sinônimo de WithFactor, com o nome usado por outras
+// bibliotecas.
+func WithMultiplier(f float64) Option {
+	return WithFactor(f)
+}
+
+// WithRandomFactor sorteia o fator de crescimento em [min, max] a cada chamada
+// a Next, descorrelacionando clientes que falham juntos. Substitui o fator
+// fixo; Peek, Schedule e Duration usam max, o pior caso. Fatores menores que
+// 1.0 ou max < min são rejeitados por NewValidated e ajustados por New.
+func WithRandomFactor(min, max float64) Option {
+	return func(b *Backoff) {
+		b.factorMin = min
+		b.factor = max
+		b.randFactor = true
+	}
+}
+
+// WithMax define o limite superior. Zero significa sem limite.
+func WithMax(d time.Duration) Option {
+	return func(b *Backoff) {
+		b.max = d
+	}
+}
+
+// WithNoMaxCap remove o limite superior, deixando o intervalo crescer até o
+// maior Duration representável, como max zero. Deixa explícita a intenção de
+// crescer sem limite, em vez de um max enorme escolhido a esmo, e sobrepõe o
+// max passado ao construtor.
+func WithNoMaxCap() Option {
+	return func(b *Backoff) {
+		b.max = 0
+	}
+}
+
+// WithJitter desabilita ou habilita o jitter. Equivale a
+// WithJitterStrategy(FullJitter) ou WithJitterStrategy(NoJitter).
 func WithJitter(enabled bool) Option {
 	return func(b *Backoff) {
-		b.withJitter = enabled
+		b.strategy = NoJitter
+		if enabled {
+			b.strategy = FullJitter
+		}
 	}
 }
 
-// Next retorna o próximo intervalo, aplicando fator e jitter (se habilitado).
-func (b *Backoff) Next() time.Duration {
-	b.mu.Lock()
-	defer b.mu.Unlock()
+// JitterStrategy define como o jitter é aplicado ao intervalo.
+type JitterStrategy int
 
-	// primeira chamada
-	if !b.initialized {
-		b.current = b.initial
-		b.initialized = true
-	} else {
-		// calcula expoencial
-		next := time.Duration(float64(b.current) * b.factor)
-		if next > b.max {
-			next = b.max
+const (
+	// FullJitter sorteia em [0, current] (padrão).
+	FullJitter JitterStrategy = iota
+	// Decorrelated sorteia em [initial, anterior*3] limitado a max, como
+	// descrito pela AWS.
+	Decorrelated
+	// NoJitter retorna o intervalo base sem aleatoriedade.
+	NoJitter
+	// EqualJitter sorteia em [current/2, current].
+	EqualJitter
+	// ProportionalJitter sorteia em [current*(1-f), current*(1+f)] limitado a
+	// max, com f definido por WithJitterFactor.
+	ProportionalJitter
+	// UpwardJitter sorteia em [current, current*(1+f)] limitado a max, com f
+	// definido por WithJitterFactor; nunca retorna menos que o intervalo base.
+	UpwardJitter
+)
+
+// String retorna o nome da estratégia.
+func (s JitterStrategy) String() string {
+	switch s {
+	case FullJitter:
+		return "full"
+	case Decorrelated:
+		return "decorrelated"
+	case NoJitter:
+		return "none"
+	case EqualJitter:
+		return "equal"
+	case ProportionalJitter:
+		return "proportional"
+	case UpwardJitter:
+		return "upward"
+	default:
+		return "JitterStrategy(" + strconv.Itoa(int(s)) + ")"
+	}
+}
+
+// WithJitterStrategy define a estratégia de jitter.
+func WithJitterStrategy(s JitterStrategy) Option {
+	return func(b *Backoff) {
+		b.strategy = s
+	}
+}
+
+// WithJitterFactor aplica jitter de ±f em torno do intervalo base, com f em
+// [0, 1]. WithJitterFactor(0) equivale a WithJitter(false). Valores fora do
+// intervalo são rejeitados por NewValidated e limitados a [0, 1] por New.
+// Com UpwardJitter apenas define f, mantendo a estratégia.
+func WithJitterFactor(f float64) Option {
+	return func(b *Backoff) {
+		b.jitterFrac = f
+		if b.strategy == UpwardJitter {
+			return
+		}
+		b.strategy = ProportionalJitter
+		if f == 0 {
+			b.strategy = NoJitter
 		}
-		b.current = next
 	}
+}
 
-	// aplica jitter completo: [0, current)
-	if b.withJitter {
-		return time.Duration(rand.Int63n(int64(b.current + 1)))
+// WithLinearStep troca o crescimento exponencial pelo linear, somando step ao
+// intervalo a cada chamada. Zero mantém o modo exponencial.
+func WithLinearStep(step time.Duration) Option {
+	return func(b *Backoff) {
+		b.step = step
 	}
-	return b.current
 }
 
-// Reset reinicia o estado para a primeira chamada.
-func (b *Backoff) Reset() {
-	b.mu.Lock()
-	defer b.mu.Unlock()
-	b.initialized = false
+// GrowthFunc calcula o intervalo base da tentativa attempt (1, 2, ...) a
+// partir do anterior, prev. Deve ser monotônica e limitada; o resultado é
+// limitado a [0, max] e o jitter é aplicado em seguida. Sem limite superior,
+// max é o maior Duration representável.
+type GrowthFunc func(attempt int, prev, initial, max time.Duration) time.Duration
+
+// WithGrowthFunc substitui o crescimento exponencial por fn. A primeira
+// chamada a Next continua retornando initial.
+func WithGrowthFunc(fn GrowthFunc) Option {
+	return func(b *Backoff) {
+		b.growth = fn
+	}
+}
+
+// WithDecreaseFactor define o fator aplicado a current por Success, em
+// (0, 1). O padrão é 0.5.
+func WithDecreaseFactor(f float64) Option {
+	return func(b *Backoff) {
+		b.decrease = f
+	}
+}
+
+// WithRand substitui a fonte aleatória do jitter. Útil para obter sequências
+// reproduzíveis sem alterar a fonte global.
+func WithRand(r *rand.Rand) Option {
+	return func(b *Backoff) {
+		b.rnd = r
+		b.crypto = false
+	}
+}
+
+// WithSeed inicializa a fonte aleatória do jitter com seed. Backoffs com a
+// mesma configuração e seed produzem a mesma sequência.
+func WithSeed(seed int64) Option {
+	return WithRand(rand.New(rand.NewPCG(uint64(seed), 0)))
+}
+
+// WithCryptoJitter sorteia o jitter com crypto/rand, tornando-o imprevisível.
+// Cada sorteio lê do sistema operacional, o que é mais lento que a fonte
+// padrão; se a leitura falhar usa a fonte global de math/rand/v2.
+func WithCryptoJitter() Option {
+	return func(b *Backoff) {
+		b.rnd = rand.New(cryptoSource{})
+		b.crypto = true
+	}
+}
+
+// WithMinDelay garante que nenhum intervalo retornado seja menor que d, com ou
+// sem jitter. O piso nunca ultrapassa max.
+func WithMinDelay(d time.Duration) Option {
+	return func(b *Backoff) {
+		b.minDelay = d
+	}
+}
+
+// WithInitialJitterSpread soma à primeira chamada a Next, desde a criação ou o
+// último Reset, um deslocamento aleatório em [0, d], escalonando clientes que
+// iniciam ao mesmo tempo. As chamadas seguintes não são afetadas.
+func WithInitialJitterSpread(d time.Duration) Option {
+	return func(b *Backoff) {
+		b.spread = d
+	}
+}
+
+// WithStrictMax garante que o intervalo retornado nunca ultrapasse max,
+// limitando o resultado final depois de jitter e deslocamentos como o de
+// WithInitialJitterSpread. O jitter sozinho já respeita max; sem esta opção o
+// deslocamento inicial pode excedê-lo.
+func WithStrictMax(strict bool) Option {
+	return func(b *Backoff) {
+		b.strictMax = strict
+	}
+}
+
+// WithMaxInterval define o limite superior do intervalo, como o parâmetro max
+// de New, e ativa WithStrictMax: nenhum valor retornado passa de d, nem mesmo
+// o primeiro quando initial é maior que d, que New aceita e usa como está por
+// compatibilidade. Zero significa sem limite superior.
+func WithMaxInterval(d time.Duration) Option {
+	return func(b *Backoff) {
+		b.max = d
+		b.strictMax = true
+	}
+}
+
+// WithFirstJitterFloor faz a primeira chamada a Next, desde a criação ou o
+// último Reset, sortear em [initial/2, initial] em vez de [0, initial] quando
+// a estratégia é FullJitter, como em EqualJitter. O primeiro retry continua
+// aleatório, mas nunca é imediato; as chamadas seguintes seguem o jitter
+// completo normal.
+func WithFirstJitterFloor(on bool) Option {
+	return func(b *Backoff) {
+		b.firstFloor = on
+	}
+}
+
+// WithJitterWindow aplica um desvio absoluto em vez da estratégia de jitter:
+// o intervalo é base + rand(-d, +d), limitado a [0, max]. Útil para quem
+// pensa em "±250ms" em vez de uma fração. Zero desliga a janela; valores
+// negativos viram zero em New e são rejeitados por NewValidated.
+func WithJitterWindow(d time.Duration) Option {
+	return func(b *Backoff) {
+		b.window = d
+	}
+}
+
+// WithOnMax registra fn para ser chamada uma única vez quando o intervalo base
+// atinge max, e de novo apenas se o backoff saturar outra vez depois de um
+// Reset. Serve para alertas. Como WithObserver, fn roda fora do lock.
+func WithOnMax(fn func()) Option {
+	return func(b *Backoff) {
+		b.onMax = fn
+	}
+}
+
+// WithName define um rótulo, como "db" ou "cache", para distinguir vários
+// Backoff em logs e métricas. O nome aparece em String, é passado ao
+// observador de WithNamedObserver e pode ser lido com Name dentro dos demais
+// callbacks, como o de WithOnMax, que rodam fora do lock.
+func WithName(name string) Option {
+	return func(b *Backoff) {
+		b.name = name
+	}
+}
+
+// WithSoftCap suaviza a chegada a max: acima de softMax o crescimento é
+// amortecido, e a cada chamada o intervalo percorre só parte da distância que
+// falta até max (metade, com fator 2.0), em vez de saltar direto para o
+// limite. Vale para o crescimento exponencial e exige max não zero; softMax
+// fora de (0, max) é ignorado por New e rejeitado por NewValidated.
+func WithSoftCap(softMax time.Duration) Option {
+	return func(b *Backoff) {
+		b.softCap = softMax
+	}
+}
+
+// WithTruncatedExponential fixa o backoff exponencial truncado da
+// especificação do Google Cloud: na tentativa n (0, 1, 2, ...) o intervalo é
+// random(0, min(max, initial*2^n)). Define fator 2.0 e FullJitter e desliga o
+// que alteraria a fórmula: modo linear, função de crescimento, fator
+// sorteado, janela de jitter, suavização de WithSoftCap e piso da primeira
+// tentativa. Opções posteriores ainda podem mudar esses valores.
+func WithTruncatedExponential() Option {
+	return func(b *Backoff) {
+		b.factor = 2.0
+		b.strategy = FullJitter
+		b.step = 0
+		b.growth = nil
+		b.randFactor = false
+		b.window = 0
+		b.softCap = 0
+		b.firstFloor = false
+	}
+}
+
+// WithFirstDelay faz a primeira chamada a Next, desde a criação ou o último
+// Reset, usar d como intervalo base; a segunda volta a initial e as seguintes
+// crescem a partir dela, initial*factor^(n-1). Separa um primeiro retry rápido
+// em partidas a frio da base do crescimento. O jitter continua valendo. Zero
+// desliga a opção. Substitui WithImmediateFirst.
+func WithFirstDelay(d time.Duration) Option {
+	return func(b *Backoff) {
+		b.firstDelay = d
+		b.immediate = false
+	}
+}
+
+// WithImmediateFirst faz a primeira chamada a Next, desde a criação ou o
+// último Reset, retornar zero, sem jitter, piso ou deslocamento; a segunda
+// retorna initial e as seguintes crescem a partir dela. Modela "tenta agora,
+// depois recua", como em laços de health check. Substitui WithFirstDelay.
+func WithImmediateFirst() Option {
+	return func(b *Backoff) {
+		b.immediate = true
+		b.firstDelay = 0
+	}
+}
+
+// WithGrowthFromAttempt escolhe de onde vem o crescimento. Com true, o padrão,
+// o intervalo base só depende da tentativa, initial*factor^attempt: o jitter
+// é aplicado sobre a base e nunca a realimenta, então o valor sorteado numa
+// chamada não afeta as seguintes. Com false o crescimento é realimentado: a
+// base seguinte é o último intervalo retornado, já com jitter, vezes factor,
+// nunca menos que initial, de modo que sorteios baixos desaceleram a
+// progressão. Schedule, Duration e TotalDelay ignoram a realimentação, que
+// depende dos sorteios.
+func WithGrowthFromAttempt(on bool) Option {
+	return func(b *Backoff) {
+		b.feedback = !on
+	}
+}
+
+// WithRounding arredonda cada intervalo retornado, já com jitter, ao
+// múltiplo mais próximo de granularity, por exemplo 10ms, para logs mais
+// limpos e timers alinhados. O arredondamento nunca leva o intervalo acima de
+// max, caso em que arredonda para baixo, nem abaixo do piso de WithMinDelay,
+// caso em que arredonda para cima; se nenhum múltiplo couber entre os dois, o
+// intervalo não é arredondado. Zero desliga a opção.
+func WithRounding(granularity time.Duration) Option {
+	return func(b *Backoff) {
+		b.rounding = granularity
+	}
+}
+
+// WithMaxAttempts limita o número de tentativas aceitas por NextOK.
+// Zero significa ilimitado.
+func WithMaxAttempts(n int) Option {
+	return func(b *Backoff) {
+		b.maxAttempts = n
+	}
+}
+
+// WithMaxElapsedTime limita o tempo total, medido a partir da primeira chamada
+// a Next, após o qual Stop retorna true e NextOK retorna false. Zero significa
+// ilimitado.
+func WithMaxElapsedTime(d time.Duration) Option {
+	return func(b *Backoff) {
+		b.maxElapsed = d
+	}
+}
+
+// WithContext associa ctx ao Backoff: quando ctx termina, NextOK e Continue
+// retornam false. O contexto é apenas consultado a cada chamada, sem
+// goroutines para observá-lo. Clones compartilham o mesmo contexto.
+func WithContext(ctx context.Context) Option {
+	return func(b *Backoff) {
+		b.ctx = ctx
+	}
+}
+
+// WithMaxTotalDelay limita a soma dos intervalos retornados: NextOK retorna
+// false, sem avançar, quando o próximo intervalo faria a soma ultrapassar d.
+// Diferente de WithMaxElapsedTime, o tempo gasto entre as chamadas não conta.
+// Zero significa ilimitado.
+func WithMaxTotalDelay(d time.Duration) Option {
+	return func(b *Backoff) {
+		b.maxTotal = d
+	}
+}
+
+// WithObserver registra fn, chamada com o número da tentativa e o intervalo
+// após cada chamada que avança o estado (Next, NextOK, NextN, ...). fn roda
+// fora do mutex, então pode chamar métodos do Backoff, mas atrasa quem chamou
+// Next: envie para métricas sem bloquear.
+func WithObserver(fn func(attempt int, delay time.Duration)) Option {
+	return func(b *Backoff) {
+		b.observer = fn
+	}
+}
+
+// WithNamedObserver funciona como WithObserver, mas fn também recebe o nome
+// de WithName, de modo que um único observador compartilhado por vários
+// Backoff, por exemplo um que alimenta métricas, sabe de qual deles veio cada
+// intervalo. Pode ser usado junto com WithObserver; os dois são chamados.
+func WithNamedObserver(fn func(name string, attempt int, delay time.Duration)) Option {
+	return func(b *Backoff) {
+		b.named = fn
+	}
+}
+
+// Next retorna o próximo intervalo, aplicando fator e jitter (se habilitado).
+// Sem jitter e sem opções que dependam de estado adicional, Next não trava o
+// mutex e avança o estado com operações atômicas.
+func (b *Backoff) Next() time.Duration {
+	b.setup()
+	if b.lockFree() && b.stagger.Load() == 0 {
+		b.markStart()
+		d, n := b.advance()
+		d = b.round(b.clampMax(b.floor(d)))
+		b.observe(n, d)
+		return d
+	}
+
+	b.mu.Lock()
+	d := b.next()
+	n := int(b.attempt.Load())
+	b.mu.Unlock()
+
+	b.observe(n, d)
+	return d
+}
+
+// NextN avança o estado n vezes e retorna os intervalos, com jitter aplicado
+// a cada um. Diferente de Schedule, altera o estado do Backoff.
+func (b *Backoff) NextN(n int) []time.Duration {
+	b.setup()
+	if n <= 0 {
+		return nil
+	}
+
+	b.mu.Lock()
+	s := make([]time.Duration, n)
+	for i := range s {
+		s[i] = b.next()
+	}
+	last := int(b.attempt.Load())
+	b.mu.Unlock()
+
+	for i, d := range s {
+		b.observe(last-n+i+1, d)
+	}
+	return s
+}
+
+// NextAfter avança o estado como Next e retorna o maior valor entre o
+// intervalo calculado e hint, por exemplo a espera sugerida pelo servidor em
+// um Retry-After.
+func (b *Backoff) NextAfter(hint time.Duration) time.Duration {
+	return max(b.Next(), hint)
+}
+
+// NextBefore avança o estado como Next e limita o intervalo ao tempo que
+// resta até deadline. Retorna false, sem avançar, se não restar tempo.
+func (b *Backoff) NextBefore(deadline time.Time) (time.Duration, bool) {
+	b.setup()
+	b.mu.Lock()
+	remaining := deadline.Sub(b.getClock().Now())
+	if remaining <= 0 {
+		b.mu.Unlock()
+		return 0, false
+	}
+	d := min(b.next(), remaining)
+	n := int(b.attempt.Load())
+	b.mu.Unlock()
+
+	b.observe(n, d)
+	return d, true
+}
+
+// NextDeadline avança o estado uma vez, como Next, e retorna o instante em que
+// a espera termina segundo o relógio do Backoff, para agendadores que
+// trabalham com horários absolutos.
+func (b *Backoff) NextDeadline() time.Time {
+	d := b.Next()
+	return b.getClock().Now().Add(d)
+}
+
+// NextContext avança o estado como Next e retorna o intervalo, sem dormir. Se
+// ctx já tiver terminado retorna ctx.Err() sem avançar, para quem faz a
+// própria espera.
+func (b *Backoff) NextContext(ctx context.Context) (time.Duration, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+	return b.Next(), nil
+}
+
+// NextOK funciona como Next, mas retorna false quando o número máximo de
+// tentativas (WithMaxAttempts) ou o tempo total (WithMaxElapsedTime) foi
+// esgotado, o contexto de WithContext terminou ou o intervalo faria a soma
+// ultrapassar WithMaxTotalDelay.
+func (b *Backoff) NextOK() (time.Duration, bool) {
+	b.setup()
+	b.mu.Lock()
+	d, ok := b.nextOK()
+	n := int(b.attempt.Load())
+	b.mu.Unlock()
+
+	if ok {
+		b.observe(n, d)
+	}
+	return d, ok
+}
+
+// nextOK implementa NextOK; exige b.mu travado.
+func (b *Backoff) nextOK() (time.Duration, bool) {
+	if b.exhausted() {
+		return 0, false
+	}
+	if b.maxTotal > 0 {
+		// sorteia antes de avançar para só consumir a tentativa se couber; a
+		// mesma base vai para o estado, sem sortear fator ou escolha de novo
+		v, base := b.nextBase()
+		d := b.round(b.clampMax(b.offset() + b.draw(base, b.fresh())))
+		if d > b.maxTotal-b.total || !b.allow() {
+			return 0, false
+		}
+		// com WithMaxTotalDelay Next sempre trava b.mu, então commit não falha
+		b.commit(v, base)
+		return b.record(d), true
+	}
+	if !b.allow() {
+		return 0, false
+	}
+	return b.next(), true
+}
+
+// allow consome uma ficha do orçamento de WithBudget, se houver.
+func (b *Backoff) allow() bool {
+	return b.budget == nil || b.budget.Allow()
+}
+
+// ErrExhausted indica que os limites verificados por NextOK foram atingidos.
+var ErrExhausted = errors.New("backoff: exhausted")
+
+// Try funciona como NextOK, mas sinaliza o fim com ErrExhausted, para quem já
+// propaga erros.
+func (b *Backoff) Try() (time.Duration, error) {
+	d, ok := b.NextOK()
+	if !ok {
+		return 0, ErrExhausted
+	}
+	return d, nil
+}
+
+// Remaining retorna quantas tentativas restam antes de NextOK retornar false
+// pelo limite de WithMaxAttempts, ou -1 quando ilimitado.
+func (b *Backoff) Remaining() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.maxAttempts <= 0 {
+		return -1
+	}
+	return max(b.maxAttempts-int(b.attempt.Load()), 0)
+}
+
+// Drain retorna os intervalos base que ainda restam pelo limite de
+// WithMaxAttempts, a partir do estado atual e sem jitter, e encerra a
+// política: o contador de tentativas passa ao limite, de modo que NextOK
+// retorna false dali em diante. Útil no desligamento gracioso, para registrar
+// o que deixou de ser tentado. Com Weighted o primeiro valor é a escolha já
+// sorteada para o próximo Next e os seguintes, que dependeriam de sorteios
+// futuros, são o maior dos intervalos. Para não alocar sem limite com valores
+// enormes de WithMaxAttempts, a lista traz no máximo drainLimit (65536)
+// valores, os primeiros da agenda. Sem limite de tentativas não há agenda
+// finita: retorna uma lista vazia e não altera o estado.
+func (b *Backoff) Drain() []time.Duration {
+	b.setup()
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.maxAttempts <= 0 {
+		return nil
+	}
+	attempt := int(b.attempt.Load())
+	n := b.maxAttempts - attempt
+	if n <= 0 {
+		return nil
+	}
+	n = min(n, drainLimit)
+	s := []time.Duration{b.peek()}
+	fresh := b.fresh()
+	for i := 1; i < n; i++ {
+		var d time.Duration
+		switch {
+		case b.choices != nil:
+			// sorteios futuros: o pior caso, sem consumir a fonte aleatória
+			d = b.scaled(slices.Max(b.choices))
+		case fresh && b.leading() && i == 1:
+			d = b.initialDelay()
+		default:
+			d = b.grow(attempt+i, s[i-1], b.growthFactor())
+		}
+		s = append(s, d)
+	}
+	b.attempt.Store(int64(b.maxAttempts))
+	return s
+}
+
+// drainLimit é o maior número de valores retornados por Drain.
+const drainLimit = 1 << 16
+
+// Stop informa se o tempo configurado com WithMaxElapsedTime foi excedido.
+func (b *Backoff) Stop() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.expired()
+}
+
+// Continue informa se ainda há tentativas, ou seja, se nenhum limite de
+// NextOK foi atingido e o contexto de WithContext não terminou. Não avança o
+// estado; permite laços como for b.Continue() { ... }.
+func (b *Backoff) Continue() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return !b.exhausted()
+}
+
+// exhausted informa se algum limite foi atingido; exige b.mu travado.
+func (b *Backoff) exhausted() bool {
+	if b.maxAttempts > 0 && int(b.attempt.Load()) >= b.maxAttempts {
+		return true
+	}
+	if b.ctx != nil && b.ctx.Err() != nil {
+		return true
+	}
+	return b.expired()
+}
+
+// expired informa se o tempo total foi excedido.
+func (b *Backoff) expired() bool {
+	return b.maxElapsed > 0 && b.Elapsed() > b.maxElapsed
+}
+
+// Elapsed retorna o tempo decorrido desde a primeira chamada a Next após a
+// criação ou o último Reset, medido pelo relógio do Backoff, ou zero antes
+// dela.
+func (b *Backoff) Elapsed() time.Duration {
+	start := b.start.Load()
+	if start == nil {
+		return 0
+	}
+	return b.getClock().Now().Sub(*start)
+}
+
+// markStart registra o início da sequência na primeira chamada a Next. Usa
+// compare-and-swap para funcionar também no caminho sem trava de Next.
+func (b *Backoff) markStart() {
+	if b.start.Load() == nil {
+		now := b.getClock().Now()
+		b.start.CompareAndSwap(nil, &now)
+	}
+}
+
+// Seq retorna um iterador sobre os intervalos de Next. Ele compartilha o
+// estado do Backoff e termina nas mesmas condições de NextOK; sem limites é
+// infinito.
+func (b *Backoff) Seq() iter.Seq[time.Duration] {
+	return func(yield func(time.Duration) bool) {
+		for {
+			d, ok := b.NextOK()
+			if !ok || !yield(d) {
+				return
+			}
+		}
+	}
+}
+
+// next avança o estado e retorna o intervalo; exige b.mu travado.
+func (b *Backoff) next() time.Duration {
+	first := b.fresh()
+	off := b.offset()
+	base, _ := b.advance()
+	return b.record(b.round(b.clampMax(off + b.draw(base, first))))
+}
+
+// draw sorteia o intervalo de uma chamada a Next com intervalo base base;
+// first indica a primeira chamada desde a criação ou o último Reset, que
+// depois de ResetJittered cai em [base, base+espalhamento] e com
+// WithImmediateFirst é zero; exige b.mu travado.
+func (b *Backoff) draw(base time.Duration, first bool) time.Duration {
+	if first && b.immediate {
+		return 0
+	}
+	if sp := time.Duration(b.stagger.Load()); first && sp > 0 {
+		d := b.between(0, sp)
+		if base > math.MaxInt64-d {
+			return math.MaxInt64
+		}
+		return b.floor(base + d)
+	}
+	return b.jitterFirst(base, b.last, first)
+}
+
+// clampMax limita d a max quando WithStrictMax está ativo.
+func (b *Backoff) clampMax(d time.Duration) time.Duration {
+	if b.strictMax {
+		return min(d, b.maxDelay())
+	}
+	return d
+}
+
+// round arredonda d ao múltiplo mais próximo da granularidade de
+// WithRounding, para baixo quando arredondar para cima passaria de max e para
+// cima quando arredondar para baixo ficaria abaixo do piso de WithMinDelay ou
+// SetFloorHint. Se nenhum múltiplo couber entre os dois, d fica como está.
+func (b *Backoff) round(d time.Duration) time.Duration {
+	if b.rounding <= 0 {
+		return d
+	}
+	lo := b.floor(0)
+	r := d.Round(b.rounding)
+	switch {
+	case r > d && r > b.maxDelay():
+		r = d.Truncate(b.rounding)
+	case r < d && r < lo:
+		r = d.Truncate(b.rounding) + b.rounding
+	}
+	// nenhum múltiplo cabe entre o piso e max, ou a soma estourou
+	if r != d && (r < lo || r > max(d, b.maxDelay())) {
+		return d
+	}
+	return r
+}
+
+// observe repassa o intervalo aos observadores de WithObserver e
+// WithNamedObserver, se houver, e dispara WithOnMax na primeira saturação;
+// deve ser chamada com b.mu destravado.
+func (b *Backoff) observe(attempt int, d time.Duration) {
+	if b.onMax != nil && b.atMax() && b.maxed.CompareAndSwap(false, true) {
+		b.onMax()
+	}
+	if b.observer != nil {
+		b.observer(attempt, d)
+	}
+	if b.named != nil {
+		b.named(b.name, attempt, d)
+	}
+}
+
+// offset sorteia o deslocamento de WithInitialJitterSpread, que só vale para a
+// primeira chamada desde a criação ou o último Reset; exige b.mu travado.
+func (b *Backoff) offset() time.Duration {
+	if !b.fresh() || b.spread <= 0 || b.immediate {
+		return 0
+	}
+	return b.between(0, b.spread)
+}
+
+// fresh informa se a próxima chamada a Next é a primeira desde a criação ou o
+// último Reset.
+func (b *Backoff) fresh() bool {
+	_, ok := decodeCur(b.cur.Load())
+	return !ok
+}
+
+// record registra d como o intervalo retornado, para Decorrelated e para os
+// limites de WithMaxElapsedTime e WithMaxTotalDelay, e encerra o espalhamento
+// de ResetJittered, que vale só para a primeira chamada; exige b.mu travado.
+func (b *Backoff) record(d time.Duration) time.Duration {
+	b.stagger.Store(0)
+	b.markStart()
+	b.last = d
+	b.total = min(b.total, math.MaxInt64-d) + d
+	return d
+}
+
+// lockFree informa se Next pode dispensar b.mu: sem jitter, janela de jitter,
+// deslocamento inicial, função de crescimento, fator sorteado, Weighted,
+// WithFirstDelay, WithImmediateFirst ou crescimento realimentado o próximo
+// intervalo depende só do atual, e sem WithMaxTotalDelay não há soma a
+// registrar.
+func (b *Backoff) lockFree() bool {
+	return b.strategy == NoJitter && b.window <= 0 && b.spread <= 0 &&
+		b.growth == nil && !b.randFactor && b.choices == nil && !b.leading() &&
+		!b.feedback && b.maxTotal <= 0
+}
+
+// leading informa se a primeira chamada tem intervalo base próprio, de
+// WithFirstDelay ou WithImmediateFirst, seguido de initial na segunda.
+func (b *Backoff) leading() bool {
+	return b.firstDelay > 0 || b.immediate
+}
+
+// firstBase retorna o intervalo base da primeira chamada quando leading é
+// verdadeiro.
+func (b *Backoff) firstBase() time.Duration {
+	if b.immediate {
+		return 0
+	}
+	return b.scaled(b.firstDelay)
+}
+
+// advance avança o intervalo base e o contador de tentativas e retorna o novo
+// intervalo base e o número da tentativa. O compare-and-swap permite que o
+// caminho sem trava de Next e os métodos que travam b.mu avancem o mesmo
+// estado sem perder chamadas.
+func (b *Backoff) advance() (time.Duration, int) {
+	for {
+		v, d := b.nextBase()
+		if n, ok := b.commit(v, d); ok {
+			return d, n
+		}
+	}
+}
+
+// nextBase calcula o próximo intervalo base a partir do estado atual,
+// sorteando o fator de WithRandomFactor, e retorna também o valor de b.cur
+// usado, para commit. Não altera o estado além de guardar a escolha de Weighted.
+func (b *Backoff) nextBase() (int64, time.Duration) {
+	f := b.growthFactor()
+	if b.randFactor {
+		f = b.factorMin + b.float64()*(f-b.factorMin)
+	}
+	v := b.cur.Load()
+	return v, b.peekFrom(v, f)
+}
+
+// commit grava d, calculado por nextBase a partir de v, como intervalo base
+// atual e avança o contador de tentativas, retornando o novo número da
+// tentativa. Falha, sem alterar nada, se b.cur mudou desde nextBase; com b.mu
+// travado e fora do caminho sem trava isso nunca ocorre.
+func (b *Backoff) commit(v int64, d time.Duration) (int, bool) {
+	if !b.cur.CompareAndSwap(v, encodeCur(d)) {
+		return 0, false
+	}
+	if b.leading() {
+		// com WithFirstDelay e WithImmediateFirst Next sempre trava b.mu
+		_, ok := decodeCur(v)
+		b.primed = !ok
+	}
+	if b.choices != nil {
+		// Weighted sempre trava b.mu
+		b.chosen = 0
+	}
+	return int(b.attempt.Add(1)), true
+}
+
+// jitter aplica a estratégia configurada ao intervalo base e o piso de
+// WithMinDelay; prev é o último intervalo sorteado, usado por Decorrelated.
+// Exige b.mu travado.
+func (b *Backoff) jitter(base, prev time.Duration) time.Duration {
+	if b.window > 0 {
+		return b.floor(b.windowed(base))
+	}
+
+	var d time.Duration
+	switch b.strategy {
+	case NoJitter:
+		d = base
+	case EqualJitter:
+		// [base/2, base]
+		d = b.between(base/2, base)
+	case Decorrelated:
+		// [initial, anterior*3], limitado a max
+		prev = max(prev, b.initialDelay())
+		hi := time.Duration(math.MaxInt64)
+		if prev <= hi/3 {
+			hi = 3 * prev
+		}
+		d = min(b.between(b.initialDelay(), hi), b.maxDelay())
+	case ProportionalJitter:
+		// [base*(1-f), base*(1+f)], limitado a max
+		lo := time.Duration(float64(base) * (1 - b.jitterFrac))
+		hi := b.maxDelay()
+		if h := float64(base) * (1 + b.jitterFrac); h < float64(hi) {
+			hi = time.Duration(h)
+		}
+		if hi <= lo {
+			d = hi
+			break
+		}
+		d = b.between(lo, hi)
+	case UpwardJitter:
+		// [base, base*(1+f)], limitado a max
+		hi := b.maxDelay()
+		if h := float64(base) * (1 + b.jitterFrac); h < float64(hi) {
+			hi = time.Duration(h)
+		}
+		d = b.between(base, max(hi, base))
+	default:
+		// aplica jitter completo: [0, base]
+		d = b.between(0, base)
+	}
+
+	return b.floor(d)
+}
+
+// windowed sorteia base + rand(-window, +window), limitado a [0, max], sem
+// estourar; exige b.mu travado.
+func (b *Backoff) windowed(base time.Duration) time.Duration {
+	w := min(b.window, math.MaxInt64/2)
+	d := b.between(0, 2*w) - w
+	if d > 0 && base > math.MaxInt64-d {
+		return b.maxDelay()
+	}
+	return min(max(base+d, 0), b.maxDelay())
+}
+
+// jitterFirst aplica jitter como jitter, mas com o piso de
+// WithFirstJitterFloor quando first indica a primeira tentativa; exige b.mu
+// travado.
+func (b *Backoff) jitterFirst(base, prev time.Duration, first bool) time.Duration {
+	if first && b.firstFloor && b.strategy == FullJitter && b.window <= 0 {
+		return b.floor(b.between(base/2, base))
+	}
+	return b.jitter(base, prev)
+}
+
+// floor aplica o piso de WithMinDelay, que nunca ultrapassa o limite superior,
+// e o piso ativo de SetFloorHint, que pode ultrapassá-lo.
+func (b *Backoff) floor(d time.Duration) time.Duration {
+	d = max(d, min(b.minDelay, b.maxDelay()))
+	if h := b.hint.Load(); h != nil && b.getClock().Now().Before(h.until) {
+		d = max(d, h.d)
+	}
+	return d
+}
+
+// SetFloorHint registra d como intervalo mínimo sugerido pelo servidor, por
+// exemplo por uma API com limite de taxa, válido por ttl. Enquanto não expirar,
+// Next retorna max(intervalo calculado, d), mesmo acima de max (exceto com
+// WithStrictMax). Uma sugestão menor que o piso ainda ativo é ignorada; uma
+// maior ou igual o substitui com o novo prazo. Reset não descarta o piso, que
+// só expira com o tempo. d ou ttl não positivos são ignorados.
+func (b *Backoff) SetFloorHint(d, ttl time.Duration) {
+	if d <= 0 || ttl <= 0 {
+		return
+	}
+	now := b.getClock().Now()
+	h := &floorHint{d: d, until: now.Add(ttl)}
+	for {
+		old := b.hint.Load()
+		if old != nil && now.Before(old.until) && old.d > d {
+			return
+		}
+		if old != nil && old.d == d && old.until.After(h.until) {
+			return
+		}
+		if b.hint.CompareAndSwap(old, h) {
+			return
+		}
+	}
+}
+
+// between sorteia em [lo, hi], sem estourar quando o intervalo ocupa todo o
+// int64; exige b.mu travado.
+func (b *Backoff) between(lo, hi time.Duration) time.Duration {
+	n := int64(hi - lo)
+	if n < math.MaxInt64 {
+		n++
+	}
+	return lo + time.Duration(b.int64n(n))
+}
+
+// newRand cria uma fonte aleatória por instância, evitando disputa entre
+// instâncias diferentes por uma fonte compartilhada.
+func newRand() *rand.Rand {
+	return rand.New(rand.NewPCG(rand.Uint64(), rand.Uint64()))
+}
+
+// cryptoSource é uma rand.Source baseada em crypto/rand.
+type cryptoSource struct{}
+
+func (cryptoSource) Uint64() uint64 {
+	var buf [8]byte
+	if _, err := crand.Read(buf[:]); err != nil {
+		return rand.Uint64()
+	}
+	return binary.LittleEndian.Uint64(buf[:])
+}
+
+// int64n sorteia em [0, n) usando a fonte do Backoff; exige b.mu travado.
+func (b *Backoff) int64n(n int64) int64 {
+	if b.rnd != nil {
+		return b.rnd.Int64N(n)
+	}
+	return rand.Int64N(n)
+}
+
+// float64 sorteia em [0, 1) usando a fonte do Backoff; exige b.mu travado.
+func (b *Backoff) float64() float64 {
+	if b.rnd != nil {
+		return b.rnd.Float64()
+	}
+	return rand.Float64()
+}
+
+// Current retorna o intervalo base, sem jitter, da última chamada a Next, ou
+// initial antes da primeira chamada.
+func (b *Backoff) Current() time.Duration {
+	b.setup()
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	cur, ok := decodeCur(b.cur.Load())
+	if !ok {
+		return b.initialDelay()
+	}
+	return cur
+}
+
+// AtMax informa se o intervalo base da última chamada a Next atingiu max, ou
+// seja, se o backoff saturou. Não depende do jitter.
+func (b *Backoff) AtMax() bool {
+	b.setup()
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.atMax()
+}
+
+// HasMaxCap informa se há um limite superior efetivo, ou seja, se max não é
+// zero (ou WithNoMaxCap) nem o maior Duration representável.
+func (b *Backoff) HasMaxCap() bool {
+	b.setup()
+	m := b.maxValue()
+	return m != 0 && m != math.MaxInt64
+}
+
+// atMax implementa AtMax sem travar b.mu.
+func (b *Backoff) atMax() bool {
+	cur, ok := decodeCur(b.cur.Load())
+	return ok && cur >= b.maxDelay()
+}
+
+// Peek retorna o próximo intervalo base sem avançar o estado. Com FullJitter
+// e EqualJitter o valor é o limite superior da janela, ou seja, o pior caso;
+// ProportionalJitter, UpwardJitter, Decorrelated, WithJitterWindow,
+// WithInitialJitterSpread e pisos como WithMinDelay podem ultrapassá-lo. Com
+// Weighted é a escolha que o próximo Next usará, sorteada uma única vez.
+func (b *Backoff) Peek() time.Duration {
+	b.setup()
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.peek()
+}
+
+// peek calcula o próximo intervalo base; exige b.mu travado.
+func (b *Backoff) peek() time.Duration {
+	return b.peekFrom(b.cur.Load(), b.growthFactor())
+}
+
+// peekFrom calcula o intervalo base seguinte a v, valor de b.cur, com o fator
+// factor. Com crescimento realimentado lê b.last e exige b.mu travado.
+func (b *Backoff) peekFrom(v int64, factor float64) time.Duration {
+	if b.choices != nil {
+		return b.choice()
+	}
+	cur, ok := decodeCur(v)
+	// primeira chamada
+	if !ok {
+		if b.leading() {
+			return b.firstBase()
+		}
+		return b.initialDelay()
+	}
+	// segunda chamada com WithFirstDelay ou WithImmediateFirst: volta à base
+	// do crescimento
+	if b.leading() && b.primed {
+		return b.initialDelay()
+	}
+	// crescimento realimentado pelo último intervalo sorteado
+	if b.feedback {
+		cur = max(b.last, b.initialDelay())
+	}
+	return b.grow(int(b.attempt.Load()), cur, factor)
+}
+
+// grow calcula o intervalo base da tentativa attempt a partir do anterior,
+// cur, com o fator factor no modo exponencial; exige b.mu travado.
+func (b *Backoff) grow(attempt int, cur time.Duration, factor float64) time.Duration {
+	// função de crescimento do usuário, limitada a [0, max]
+	if b.growth != nil {
+		return min(max(b.growth(attempt, cur, b.initialDelay(), b.maxDelay()), 0), b.maxDelay())
+	}
+
+	// modo linear: soma step
+	if b.step > 0 {
+		if cur >= b.maxDelay()-b.step {
+			return b.maxDelay()
+		}
+		return cur + b.step
+	}
+
+	// calcula expoencial; compara em float64 antes da conversão para que um
+	// overflow de int64 nunca produza valor negativo
+	next := float64(cur) * factor
+	if soft := b.scaled(b.softCap); b.softCap > 0 && next > float64(soft) && next > float64(cur) {
+		return b.ease(cur, soft, next)
+	}
+	if next >= float64(b.maxDelay()) {
+		return b.maxDelay()
+	}
+	// decaimento com fator < 1.0 para no piso de WithFloor
+	if next <= float64(b.decayFloor) {
+		return b.decayFloor
+	}
+	return time.Duration(next)
+}
+
+// Duration retorna o intervalo da tentativa attempt (0, 1, 2, ...) sem
+// depender do estado: min(max, initial*factor^attempt), ou
+// min(max, initial+step*attempt) no modo linear. O jitter é aplicado a
+// cada chamada com a fonte aleatória do Backoff; em Decorrelated o intervalo
+// anterior é aproximado pelo valor base da tentativa anterior.
+func (b *Backoff) Duration(attempt int) time.Duration {
+	b.setup()
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	attempt = max(attempt, 0)
+	var prev time.Duration
+	if attempt > 0 {
+		prev = b.base(attempt - 1)
+	}
+	return b.jitterFirst(b.base(attempt), prev, attempt == 0)
+}
+
+// SampleJitter retorna n valores sorteados pela estratégia de jitter e pela
+// fonte aleatória do Backoff para o intervalo base, sem avançar o estado. Em
+// Decorrelated base faz o papel do intervalo anterior. Serve principalmente
+// para testar a distribuição do jitter.
+func (b *Backoff) SampleJitter(base time.Duration, n int) []time.Duration {
+	b.setup()
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if n <= 0 {
+		return nil
+	}
+	s := make([]time.Duration, n)
+	for i := range s {
+		s[i] = b.jitter(base, base)
+	}
+	return s
+}
+
+// ApplyJitter aplica a estratégia de jitter a base com o mesmo algoritmo de
+// Next, sem um Backoff nem estado, para bibliotecas que calculam o próprio
+// intervalo base. r é a fonte aleatória; nil usa a fonte global. Sem limite
+// superior; em Decorrelated base faz o papel de initial e do intervalo
+// anterior, sorteando em [base, 3*base]. ProportionalJitter e UpwardJitter
+// dependem da fração de WithJitterFactor e, sem ela, retornam base. Valores
+// negativos de base são tratados como zero.
+func ApplyJitter(base time.Duration, strategy JitterStrategy, r *rand.Rand) time.Duration {
+	base = max(base, 0)
+	b := Backoff{config: config{initial: base, strategy: strategy}, rnd: r}
+	return b.jitter(base, base)
+}
+
+// ease amortece o crescimento de cur para next acima de soft: a partir de
+// max(cur, soft), percorre a fração 1-from/next da distância até max,
+// arredondada para cima para alcançar max em algum momento.
+func (b *Backoff) ease(cur, soft time.Duration, next float64) time.Duration {
+	hi := b.maxDelay()
+	from := max(cur, soft)
+	if from >= hi {
+		return hi
+	}
+	k := 1 - float64(from)/next
+	return min(from+time.Duration(math.Ceil(float64(hi-from)*k)), hi)
+}
+
+// base calcula min(max, initial*factor^attempt); exige b.mu travado.
+func (b *Backoff) base(attempt int) time.Duration {
+	if b.choices != nil {
+		return b.pick()
+	}
+	// com o primeiro intervalo destacado a progressão começa uma tentativa
+	// depois, mas a função de crescimento recebe o índice absoluto, como em
+	// Schedule e Next
+	shift := 0
+	if b.leading() {
+		if attempt == 0 {
+			return b.firstBase()
+		}
+		attempt--
+		shift = 1
+	}
+	initial := b.initialDelay()
+	if attempt == 0 {
+		return initial
+	}
+	if b.growth != nil || b.softCap > 0 {
+		d := initial
+		for i := 1; i <= attempt; i++ {
+			next := b.grow(i+shift, d, b.growthFactor())
+			// sem função de crescimento o valor só depende do anterior: ao
+			// parar de mudar, como ao saturar em max, não muda mais
+			if next == d && b.growth == nil {
+				break
+			}
+			d = next
+		}
+		return d
+	}
+	d := float64(initial) * math.Pow(b.growthFactor(), float64(attempt))
+	if b.step > 0 {
+		d = float64(initial) + float64(b.step)*float64(attempt)
+	}
+	if d >= float64(b.maxDelay()) {
+		return b.maxDelay()
+	}
+	if d <= float64(b.decayFloor) {
+		return b.decayFloor
+	}
+	return time.Duration(d)
+}
+
+// Schedule retorna os n primeiros intervalos base a partir do início, sem
+// alterar o estado. O jitter não é aplicado; com FullJitter e EqualJitter
+// cada valor é o limite superior da respectiva janela, mas as demais
+// estratégias, WithJitterWindow e WithInitialJitterSpread podem ultrapassá-lo.
+func (b *Backoff) Schedule(n int) []time.Duration {
+	b.setup()
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if n <= 0 {
+		return nil
+	}
+	s := make([]time.Duration, n)
+	s[0] = b.base(0)
+	for i := 1; i < n; i++ {
+		switch {
+		case b.choices != nil:
+			s[i] = b.pick()
+		case b.leading() && i == 1:
+			s[i] = b.initialDelay()
+		default:
+			s[i] = b.grow(i, s[i-1], b.growthFactor())
+		}
+	}
+	return s
+}
+
+// TotalDelay soma os intervalos base das primeiras attempts tentativas. Com
+// NoJitter, FullJitter e EqualJitter é a espera acumulada no pior caso; com
+// as demais estratégias, WithJitterWindow ou WithInitialJitterSpread a espera
+// real pode ser maior. Não altera o estado.
+func (b *Backoff) TotalDelay(attempts int) time.Duration {
+	var total time.Duration
+	for _, d := range b.Schedule(attempts) {
+		if total > math.MaxInt64-d {
+			return math.MaxInt64
+		}
+		total += d
+	}
+	return total
+}
+
+// EstimateTotal estima por Monte Carlo os percentis 50 e 99 da espera total,
+// com jitter, das primeiras attempts tentativas, sorteando samples sequências
+// com a fonte aleatória do Backoff. Com jitter a soma é uma variável
+// aleatória e TotalDelay, que soma os intervalos base, engana no planejamento
+// de capacidade.
+// Não altera o estado; retorna zeros se attempts ou samples não forem
+// positivos.
+func (b *Backoff) EstimateTotal(attempts, samples int) (p50, p99 time.Duration) {
+	b.setup()
+	if attempts <= 0 || samples <= 0 {
+		return 0, 0
+	}
+
+	b.mu.Lock()
+	// Weighted sorteia um intervalo base novo a cada tentativa
+	bases := make([]time.Duration, attempts)
+	if b.choices == nil {
+		for n := range bases {
+			bases[n] = b.base(n)
+		}
+	}
+	totals := make([]time.Duration, samples)
+	for i := range totals {
+		var total, prev time.Duration
+		for n, base := range bases {
+			if b.choices != nil {
+				base = b.pick()
+			}
+			d := b.round(b.clampMax(b.jitterFirst(base, prev, n == 0)))
+			total = min(total, math.MaxInt64-d) + d
+			prev = d
+		}
+		totals[i] = total
+	}
+	b.mu.Unlock()
+
+	slices.Sort(totals)
+	return percentile(totals, 0.50), percentile(totals, 0.99)
+}
+
+// percentile retorna o percentil p, em (0, 1], de s ordenado, pelo método do
+// posto mais próximo.
+func percentile(s []time.Duration, p float64) time.Duration {
+	i := int(math.Ceil(p*float64(len(s)))) - 1
+	return s[min(max(i, 0), len(s)-1)]
+}
+
+// Wait calcula o próximo intervalo e dorme por ele. Retorna ctx.Err() se o
+// contexto for cancelado antes do intervalo terminar; o estado avança uma vez
+// por chamada mesmo nesse caso.
+func (b *Backoff) Wait(ctx context.Context) error {
+	return b.sleep(ctx, b.Next())
+}
+
+// WaitN dorme pelos próximos n intervalos em sequência, chamando progress
+// (quando não nil) antes de cada espera com o índice i, a partir de 1, e o
+// intervalo d. Se o contexto for cancelado no meio, retorna um erro que
+// informa quantas esperas foram completadas e que envolve ctx.Err().
+func (b *Backoff) WaitN(ctx context.Context, n int, progress func(i int, d time.Duration)) error {
+	for i := range n {
+		d := b.Next()
+		if progress != nil {
+			progress(i+1, d)
+		}
+		if err := b.sleep(ctx, d); err != nil {
+			return fmt.Errorf("backoff: WaitN interrupted after %d of %d waits: %w", i, n, err)
+		}
+	}
+	return nil
+}
+
+// Channel retorna um canal que recebe cada intervalo depois de esperá-lo,
+// funcionando como um ticker com períodos crescentes. O canal é fechado
+// quando o contexto termina ou os limites verificados por NextOK são
+// atingidos; a goroutine interna termina junto.
+func (b *Backoff) Channel(ctx context.Context) <-chan time.Duration {
+	ch := make(chan time.Duration)
+	go func() {
+		defer close(ch)
+		for {
+			d, ok := b.NextOK()
+			if !ok || b.sleep(ctx, d) != nil {
+				return
+			}
+			select {
+			case ch <- d:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return ch
+}
+
+// WaitChan avança o estado como Next e retorna um canal que é fechado depois
+// do intervalo retornado, medido no relógio do Backoff, para uso em select
+// sem gerenciar um timer:
+//
+//	select {
+//	case <-ctx.Done():
+//		return ctx.Err()
+//	case <-b.WaitChan():
+//	}
+//
+// Cada chamada avança o Backoff, então o canal deve ser obtido uma vez por
+// espera, fora de laços que repetem o select. O timer e a goroutine internos
+// terminam quando o intervalo acaba, mesmo que o canal nunca seja lido.
+func (b *Backoff) WaitChan() <-chan struct{} {
+	ch := make(chan struct{})
+	d := b.Next()
+	if d <= 0 {
+		close(ch)
+		return ch
+	}
+	t := b.getClock().NewTimer(d)
+	go func() {
+		<-t.C()
+		close(ch)
+	}()
+	return ch
+}
+
+// sleep dorme por d, usando o relógio do Backoff, ou até o contexto ser
+// cancelado.
+func (b *Backoff) sleep(ctx context.Context, d time.Duration) error {
+	var s sleeper
+	defer s.stop()
+	return s.sleep(ctx, b.getClock(), d)
+}
+
+// sleeper reaproveita um único Timer em esperas sucessivas, evitando alocar
+// um timer novo a cada iteração de um laço de retry.
+type sleeper struct {
+	t Timer
+}
+
+// sleep dorme por d ou até o contexto ser cancelado, criando o timer na
+// primeira chamada e reiniciando-o nas seguintes.
+func (s *sleeper) sleep(ctx context.Context, c Clock, d time.Duration) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	// o timer só é reiniciado depois de disparar e ser lido, ou de ser parado
+	// e drenado, então Reset não entrega um disparo antigo
+	if s.t == nil {
+		s.t = c.NewTimer(d)
+	} else {
+		s.t.Reset(d)
+	}
+	select {
+	case <-ctx.Done():
+		s.drain()
+		return ctx.Err()
+	case <-s.t.C():
+		return nil
+	}
+}
+
+// drain para o timer e descarta um disparo pendente, para não vazá-lo nem
+// entregá-lo após um Reset.
+func (s *sleeper) drain() {
+	if !s.t.Stop() {
+		select {
+		case <-s.t.C():
+		default:
+		}
+	}
+}
+
+// stop libera o timer, se houver.
+func (s *sleeper) stop() {
+	if s.t != nil {
+		s.t.Stop()
+	}
+}
+
+// Clone retorna um novo Backoff com a mesma configuração e estado reiniciado.
+// A fonte aleatória do clone é derivada da original, sem compartilhar estado
+// mutável com ela. A escala de Scale também é copiada.
+func (b *Backoff) Clone() *Backoff {
+	b.setup()
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	c := &Backoff{config: b.config}
+	c.factor, c.max = b.growthFactor(), b.maxValue()
+	c.scale.Store(b.scale.Load())
+	switch {
+	case b.crypto:
+		c.rnd = rand.New(cryptoSource{})
+	case b.rnd != nil:
+		c.rnd = rand.New(rand.NewPCG(b.rnd.Uint64(), b.rnd.Uint64()))
+	}
+	return c
+}
+
+// String descreve a configuração e a tentativa atual, para logs, precedidas do
+// nome de WithName, se houver.
+func (b *Backoff) String() string {
+	b.setup()
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	var name string
+	if b.name != "" {
+		name = fmt.Sprintf("name=%q, ", b.name)
+	}
+	return fmt.Sprintf("Backoff(%sinitial=%v, factor=%.2f, max=%v, jitter=%v, attempt=%d)",
+		name, b.initial, b.growthFactor(), b.maxValue(), b.strategy, b.attempt.Load())
+}
+
+// Name retorna o rótulo definido por WithName, ou "" se não houver.
+func (b *Backoff) Name() string {
+	return b.name
+}
+
+// Scale estica (f > 1) ou encolhe (f < 1) initial, max e o intervalo atual
+// pelo fator f em tempo de execução, por exemplo quando o servidor pede menos
+// carga. O fator é absoluto, relativo à configuração original: Scale(2)
+// seguido de Scale(3) resulta em três vezes a original, não seis. Valores de f
+// não positivos, NaN ou infinitos são ignorados. Config e String continuam
+// reportando a configuração original.
+func (b *Backoff) Scale(f float64) {
+	if f <= 0 || math.IsNaN(f) || math.IsInf(f, 0) {
+		return
+	}
+	b.rescale(f)
+}
+
+// Unscale desfaz Scale, voltando à configuração original e dividindo o
+// intervalo atual pelo fator removido.
+func (b *Backoff) Unscale() {
+	b.rescale(1)
+}
+
+// rescale troca o fator de escala por f e ajusta o intervalo atual na mesma
+// proporção.
+func (b *Backoff) rescale(f float64) {
+	b.setup()
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	old := b.scaleFactor()
+	if f == 1 {
+		b.scale.Store(0)
+	} else {
+		b.scale.Store(math.Float64bits(f))
+	}
+	for {
+		v := b.cur.Load()
+		cur, ok := decodeCur(v)
+		if !ok {
+			return
+		}
+		d := min(scaleBy(cur, f/old), b.maxDelay())
+		if b.cur.CompareAndSwap(v, encodeCur(d)) {
+			return
+		}
+	}
+}
+
+// Reset reinicia o estado para a primeira chamada.
+func (b *Backoff) Reset() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.reset()
+}
+
+// ResetJittered reinicia o estado como Reset, mas a primeira chamada a Next
+// seguinte retorna um intervalo aleatório em [initial, initial+spread], em vez
+// do jitter da estratégia. Depois de uma queda, evita que todos os clientes
+// recomecem juntos; as chamadas seguintes crescem normalmente. spread não
+// positivo equivale a Reset.
+func (b *Backoff) ResetJittered(spread time.Duration) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.reset()
+	b.stagger.Store(int64(max(spread, 0)))
+}
+
+// ResetDelayOnly reinicia só a progressão dos intervalos: o próximo Next volta
+// a retornar initial, mas, ao contrário de Reset, Attempt continua contando
+// desde a criação, o que serve a métricas de tentativas ao longo da vida do
+// Backoff. O tempo decorrido e a soma dos intervalos também são mantidos, de
+// modo que WithMaxAttempts, WithMaxElapsedTime e WithMaxTotalDelay continuam
+// valendo para o total.
+func (b *Backoff) ResetDelayOnly() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.stagger.Store(0)
+	b.cur.Store(0)
+	b.maxed.Store(false)
+	b.primed = false
+	b.last = 0
+}
+
+// reset implementa Reset; exige b.mu travado.
+func (b *Backoff) reset() {
+	b.primed = false
+	b.stagger.Store(0)
+	b.cur.Store(0)
+	b.attempt.Store(0)
+	b.maxed.Store(false)
+	b.last = 0
+	b.start.Store(nil)
+	b.total = 0
+}
+
+// Success registra um sucesso, reduzindo current pelo fator de
+// WithDecreaseFactor até no mínimo initial. Junto com Next, que cresce a cada
+// falha, permite que o intervalo se adapte ao sucesso observado.
+func (b *Backoff) Success() {
+	b.setup()
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	cur, ok := decodeCur(b.cur.Load())
+	if !ok {
+		return
+	}
+	f := b.decrease
+	if f <= 0 || f >= 1 {
+		f = 0.5
+	}
+	b.cur.Store(encodeCur(max(time.Duration(float64(cur)*f), b.initialDelay())))
+}
+
+// Observe reinicia o backoff quando err é nil. Com erro o estado não muda; ele
+// avança na próxima chamada a Next.
+func (b *Backoff) Observe(err error) {
+	if err == nil {
+		b.Reset()
+	}
+}
+
+// ResetTo posiciona o estado como se Next já tivesse retornado o intervalo da
+// tentativa attempt (0, 1, 2, ...): current passa a min(max,
+// initial*factor^attempt), Attempt retorna attempt+1 e o próximo Next segue
+// para a tentativa seguinte. Valores negativos são tratados como 0.
+func (b *Backoff) ResetTo(attempt int) {
+	b.setup()
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	attempt = max(attempt, 0)
+	b.last = b.base(attempt)
+	b.cur.Store(encodeCur(b.last))
+	b.attempt.Store(int64(attempt) + 1)
+	b.maxed.Store(false)
+	b.stagger.Store(0)
+	b.primed = attempt == 0 && b.leading()
+	b.start.Store(nil)
+	b.total = 0
+}
+
+// Attempt retorna quantas vezes Next foi chamado desde a criação ou o último
+// Reset. Lê o contador atômico sem travar b.mu, então pode ser chamado no
+// caminho crítico sem disputar com Next.
+func (b *Backoff) Attempt() int {
+	return int(b.attempt.Load())
 }
 
 // Example of usage:
@@ -1,7 +1,13 @@
 package backoff
 
 import (
-	"math/rand"
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"reflect"
+	"slices"
 	"sync"
 	"testing"
 	"time"
@@ -54,16 +60,479 @@ func TestNew(t *testing.T) {
 			if b.max != tt.max {
 				t.Errorf("New() max = %v, want %v", b.max, tt.max)
 			}
-			if b.withJitter != tt.wantJitter {
-				t.Errorf("New() withJitter = %v, want %v", b.withJitter, tt.wantJitter)
+			if gotJitter := b.strategy != NoJitter; gotJitter != tt.wantJitter {
+				t.Errorf("New() jitter = %v, want %v", gotJitter, tt.wantJitter)
 			}
-			if b.initialized {
+			if b.Snapshot().Initialized {
 				t.Errorf("New() initialized should be false initially")
 			}
 		})
 	}
 }
 
+func TestNew_ClampsInvalidFactor(t *testing.T) {
+	for _, factor := range []float64{0.5, -2.0, math.NaN(), math.Inf(1), math.Inf(-1)} {
+		b := New(100*time.Millisecond, factor, 1*time.Second)
+		if b.factor != 1.0 {
+			t.Errorf("New() with factor %v: factor = %v, want 1.0", factor, b.factor)
+		}
+	}
+}
+
+func TestConstant(t *testing.T) {
+	b := Constant(250*time.Millisecond, WithJitter(false))
+	for i := 0; i < 10; i++ {
+		if got := b.Next(); got != 250*time.Millisecond {
+			t.Errorf("Next() call %d = %v, want %v", i+1, got, 250*time.Millisecond)
+		}
+	}
+
+	j := Constant(250 * time.Millisecond)
+	for i := 0; i < 100; i++ {
+		if got := j.Next(); got < 0 || got > 250*time.Millisecond {
+			t.Fatalf("Next() with jitter = %v, want range [0, %v]", got, 250*time.Millisecond)
+		}
+	}
+}
+
+func TestLinear(t *testing.T) {
+	tests := []struct {
+		name string
+		b    *Backoff
+		want []time.Duration
+	}{
+		{
+			name: "arithmetic progression",
+			b:    Linear(1*time.Second, 1*time.Second, 10*time.Second, WithJitter(false)),
+			want: []time.Duration{1 * time.Second, 2 * time.Second, 3 * time.Second, 4 * time.Second},
+		},
+		{
+			name: "capped at max",
+			b:    Linear(1*time.Second, 2*time.Second, 6*time.Second, WithJitter(false)),
+			want: []time.Duration{1 * time.Second, 3 * time.Second, 5 * time.Second, 6 * time.Second, 6 * time.Second},
+		},
+		{
+			name: "WithLinearStep on New",
+			b:    New(100*time.Millisecond, 2.0, 1*time.Second, WithJitter(false), WithLinearStep(50*time.Millisecond)),
+			want: []time.Duration{100 * time.Millisecond, 150 * time.Millisecond, 200 * time.Millisecond},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for i, want := range tt.want {
+				if got := tt.b.Duration(i); got != want {
+					t.Errorf("Duration(%d) = %v, want %v", i, got, want)
+				}
+				if got := tt.b.Next(); got != want {
+					t.Errorf("Next() call %d = %v, want %v", i+1, got, want)
+				}
+			}
+		})
+	}
+
+	// Jitter still applies
+	j := Linear(1*time.Second, 1*time.Second, 10*time.Second)
+	for i := 0; i < 5; i++ {
+		base := j.Peek()
+		if got := j.Next(); got < 0 || got > base {
+			t.Errorf("Next() with jitter = %v, want range [0, %v]", got, base)
+		}
+	}
+}
+
+func TestNewWith(t *testing.T) {
+	tests := []struct {
+		name        string
+		opts        []Option
+		wantInitial time.Duration
+		wantFactor  float64
+		wantMax     time.Duration
+	}{
+		{"defaults", nil, 100 * time.Millisecond, 2.0, 10 * time.Second},
+		{
+			name:        "all overridden",
+			opts:        []Option{WithInitial(1 * time.Second), WithFactor(1.5), WithMax(1 * time.Minute)},
+			wantInitial: 1 * time.Second,
+			wantFactor:  1.5,
+			wantMax:     1 * time.Minute,
+		},
+		{
+			name:        "multiplier alias",
+			opts:        []Option{WithInitial(500 * time.Millisecond), WithMultiplier(3.0), WithMax(5 * time.Second)},
+			wantInitial: 500 * time.Millisecond,
+			wantFactor:  3.0,
+			wantMax:     5 * time.Second,
+		},
+		{
+			name:        "invalid factor clamped",
+			opts:        []Option{WithFactor(0.1)},
+			wantInitial: 100 * time.Millisecond,
+			wantFactor:  1.0,
+			wantMax:     10 * time.Second,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := NewWith(tt.opts...)
+			if b.initial != tt.wantInitial || b.factor != tt.wantFactor || b.max != tt.wantMax {
+				t.Errorf("NewWith() = (%v, %v, %v), want (%v, %v, %v)",
+					b.initial, b.factor, b.max, tt.wantInitial, tt.wantFactor, tt.wantMax)
+			}
+		})
+	}
+
+	// Options compose with the positional constructor too
+	b := New(1*time.Second, 2.0, 5*time.Second, WithMax(30*time.Second), WithJitter(false))
+	if b.max != 30*time.Second || b.strategy != NoJitter {
+		t.Errorf("New() with WithMax() = max %v, jitter %v", b.max, b.strategy)
+	}
+}
+
+func TestDefault(t *testing.T) {
+	b := Default()
+
+	// keep in sync with the documented defaults
+	if b.initial != 100*time.Millisecond || b.factor != 2.0 || b.max != 30*time.Second {
+		t.Errorf("Default() = (%v, %v, %v), want (100ms, 2, 30s)", b.initial, b.factor, b.max)
+	}
+	if b.strategy != FullJitter {
+		t.Errorf("Default() strategy = %v, want %v", b.strategy, FullJitter)
+	}
+	if b.maxElapsed != 5*time.Minute {
+		t.Errorf("Default() max elapsed = %v, want %v", b.maxElapsed, 5*time.Minute)
+	}
+	if b.maxAttempts != 0 {
+		t.Errorf("Default() max attempts = %d, want unlimited", b.maxAttempts)
+	}
+
+	if b := Default(WithMaxElapsedTime(0), WithJitter(false)); b.maxElapsed != 0 || b.strategy != NoJitter {
+		t.Errorf("Default() with options = max elapsed %v, jitter %v", b.maxElapsed, b.strategy)
+	}
+}
+
+func TestGoogleCloud(t *testing.T) {
+	const (
+		base  = 1 * time.Second
+		limit = 32 * time.Second
+	)
+
+	for seed := range uint64(20) {
+		b := GoogleCloud(WithRand(rand.New(rand.NewPCG(seed, 0))))
+		ref := rand.New(rand.NewPCG(seed, 0))
+
+		for n := range 10 {
+			// random(0, min(cap, base*2^n)), inclusive bounds
+			ceiling := min(limit, base<<n)
+			want := time.Duration(ref.Int64N(int64(ceiling) + 1))
+			if got := b.Next(); got != want {
+				t.Fatalf("seed %d: Next() at attempt %d = %v, want %v", seed, n, got, want)
+			}
+		}
+	}
+
+	want := Config{Initial: base, Factor: 2.0, Max: limit, Jitter: FullJitter}
+	if got := GoogleCloud().Config(); got != want {
+		t.Errorf("Config() = %+v, want %+v", got, want)
+	}
+}
+
+func TestWithTruncatedExponential(t *testing.T) {
+	// options that would bend the formula are switched off
+	b := New(100*time.Millisecond, 1.5, 1*time.Second, WithLinearStep(time.Second),
+		WithJitterStrategy(EqualJitter), WithSoftCap(500*time.Millisecond), WithTruncatedExponential())
+
+	want := []time.Duration{100 * time.Millisecond, 200 * time.Millisecond, 400 * time.Millisecond,
+		800 * time.Millisecond, 1 * time.Second, 1 * time.Second}
+	for n, w := range want {
+		if got := b.Duration(n); got < 0 || got > w {
+			t.Errorf("Duration(%d) = %v, want range [0, %v]", n, got, w)
+		}
+	}
+	if got := b.Schedule(len(want)); !reflect.DeepEqual(got, want) {
+		t.Errorf("Schedule() = %v, want %v", got, want)
+	}
+	if got := b.Config().Jitter; got != FullJitter {
+		t.Errorf("Config().Jitter = %v, want %v", got, FullJitter)
+	}
+}
+
+func TestNewValidated(t *testing.T) {
+	tests := []struct {
+		name    string
+		initial time.Duration
+		factor  float64
+		max     time.Duration
+		wantErr bool
+	}{
+		{"valid", 100 * time.Millisecond, 2.0, 1 * time.Second, false},
+		{"factor of 1.0", 100 * time.Millisecond, 1.0, 1 * time.Second, false},
+		{"max equal to initial", 1 * time.Second, 2.0, 1 * time.Second, false},
+		{"zero initial", 0, 2.0, 1 * time.Second, true},
+		{"negative initial", -1 * time.Second, 2.0, 1 * time.Second, true},
+		{"factor below 1.0", 100 * time.Millisecond, 0.5, 1 * time.Second, true},
+		{"NaN factor", 100 * time.Millisecond, math.NaN(), 1 * time.Second, true},
+		{"infinite factor", 100 * time.Millisecond, math.Inf(1), 1 * time.Second, true},
+		{"max less than initial", 1 * time.Second, 2.0, 500 * time.Millisecond, true},
+		{"zero max is unbounded", 1 * time.Second, 2.0, 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := NewValidated(tt.initial, tt.factor, tt.max)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NewValidated() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				if b != nil {
+					t.Errorf("NewValidated() returned non-nil Backoff on error")
+				}
+				return
+			}
+			if b.initial != tt.initial || b.factor != tt.factor || b.max != tt.max {
+				t.Errorf("NewValidated() = (%v, %v, %v), want (%v, %v, %v)",
+					b.initial, b.factor, b.max, tt.initial, tt.factor, tt.max)
+			}
+		})
+	}
+}
+
+func TestBackoff_ZeroValue(t *testing.T) {
+	var b Backoff
+
+	want := Config{Initial: 100 * time.Millisecond, Factor: 2.0, Max: 30 * time.Second, Jitter: FullJitter}
+	for i := range 20 {
+		base := b.Peek()
+		if d := b.Next(); d < 0 || d > base {
+			t.Fatalf("Next() call %d = %v, want range [0, %v]", i+1, d, base)
+		}
+	}
+	if got := b.Config(); got != want {
+		t.Errorf("Config() = %+v, want %+v", got, want)
+	}
+	if !b.AtMax() {
+		t.Errorf("AtMax() after 20 calls = false, want true")
+	}
+
+	// an embedding struct works without a constructor
+	var client struct {
+		retry Backoff
+	}
+	if got := client.retry.Schedule(3); !reflect.DeepEqual(got, []time.Duration{100 * time.Millisecond, 200 * time.Millisecond, 400 * time.Millisecond}) {
+		t.Errorf("Schedule(3) = %v, want [100ms 200ms 400ms]", got)
+	}
+
+	// lazy defaults are applied once, even under concurrent first calls
+	var shared Backoff
+	var wg sync.WaitGroup
+	for range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if d := shared.Next(); d > 100*time.Millisecond*(1<<10) {
+				t.Errorf("Next() = %v, too large", d)
+			}
+		}()
+	}
+	wg.Wait()
+	if got := shared.Attempt(); got != 8 {
+		t.Errorf("Attempt() = %d, want 8", got)
+	}
+}
+
+func TestWeighted(t *testing.T) {
+	choices := []time.Duration{100 * time.Millisecond, 500 * time.Millisecond, 2 * time.Second}
+	weights := []float64{1, 2, 7}
+	const n = 100000
+
+	var p Policy = Weighted(choices, weights, WithSeed(42))
+	counts := map[time.Duration]int{}
+	for range n {
+		counts[p.Next()]++
+	}
+	if len(counts) != len(choices) {
+		t.Fatalf("Next() drew %d distinct values %v, want exactly %v", len(counts), counts, choices)
+	}
+	for i, c := range choices {
+		got := float64(counts[c]) / n
+		want := weights[i] / 10
+		if math.Abs(got-want) > 0.01 {
+			t.Errorf("frequency of %v = %.3f, want %.3f", c, got, want)
+		}
+	}
+
+	b := Weighted(choices, weights)
+	if got, want := b.Config(), (Config{Initial: choices[0], Factor: 1.0, Max: choices[2], Jitter: NoJitter}); got != want {
+		t.Errorf("Config() = %+v, want %+v", got, want)
+	}
+	distinct := map[time.Duration]bool{}
+	for _, d := range b.Schedule(20) {
+		if !slices.Contains(choices, d) {
+			t.Errorf("Schedule() value %v, want one of %v", d, choices)
+		}
+		distinct[d] = true
+	}
+	if len(distinct) < 2 {
+		t.Errorf("Schedule() drew %d distinct values, want weighted draws", len(distinct))
+	}
+}
+
+func TestWeighted_SingleDraw(t *testing.T) {
+	choices := []time.Duration{100 * time.Millisecond, 500 * time.Millisecond, 2 * time.Second}
+	weights := []float64{1, 1, 1}
+
+	t.Run("NextOK with total limit matches state", func(t *testing.T) {
+		b := Weighted(choices, weights, WithSeed(7), WithMaxTotalDelay(time.Hour))
+		for i := range 50 {
+			d, _ := b.NextOK()
+			if got := b.Current(); got != d {
+				t.Fatalf("NextOK() %d = %v, but Current() = %v", i+1, d, got)
+			}
+		}
+	})
+
+	t.Run("Peek reports the next draw", func(t *testing.T) {
+		b := Weighted(choices, weights, WithSeed(7))
+		for i := range 50 {
+			want := b.Peek()
+			if again := b.Peek(); again != want {
+				t.Fatalf("step %d: Peek() = %v then %v, want a stable value", i+1, want, again)
+			}
+			if got := b.Next(); got != want {
+				t.Fatalf("step %d: Next() = %v, want Peek() %v", i+1, got, want)
+			}
+		}
+	})
+
+	t.Run("Peek does not consume draws", func(t *testing.T) {
+		peeked := Weighted(choices, weights, WithSeed(7))
+		plain := Weighted(choices, weights, WithSeed(7))
+		for i := range 50 {
+			peeked.Peek()
+			peeked.Peek()
+			if got, want := peeked.Next(), plain.Next(); got != want {
+				t.Fatalf("step %d: Next() after Peek() = %v, want %v", i+1, got, want)
+			}
+		}
+	})
+
+	t.Run("Drain", func(t *testing.T) {
+		b := Weighted(choices, weights, WithSeed(7), WithMaxAttempts(4))
+		next := b.Peek()
+		want := []time.Duration{next, 2 * time.Second, 2 * time.Second, 2 * time.Second}
+		if got := b.Drain(); !reflect.DeepEqual(got, want) {
+			t.Errorf("Drain() = %v, want %v", got, want)
+		}
+	})
+}
+
+func TestWeighted_Validation(t *testing.T) {
+	tests := []struct {
+		name    string
+		choices []time.Duration
+		weights []float64
+	}{
+		{"empty", nil, nil},
+		{"length mismatch", []time.Duration{time.Second, 2 * time.Second}, []float64{1}},
+		{"zero weight", []time.Duration{time.Second}, []float64{0}},
+		{"negative weight", []time.Duration{time.Second}, []float64{-1}},
+		{"NaN weight", []time.Duration{time.Second}, []float64{math.NaN()}},
+		{"negative choice", []time.Duration{-time.Second}, []float64{1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer func() {
+				if r := recover(); r == nil {
+					t.Errorf("Weighted() did not panic")
+				}
+			}()
+			Weighted(tt.choices, tt.weights)
+		})
+	}
+}
+
+func TestMustNew(t *testing.T) {
+	tests := []struct {
+		name      string
+		initial   time.Duration
+		factor    float64
+		max       time.Duration
+		wantPanic string
+	}{
+		{"valid", 100 * time.Millisecond, 2.0, 1 * time.Second, ""},
+		{"zero initial", 0, 2.0, 1 * time.Second, "backoff: initial must be positive, got 0s"},
+		{"max less than initial", 1 * time.Second, 2.0, 500 * time.Millisecond,
+			"backoff: max (500ms) must not be less than initial (1s)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer func() {
+				r := recover()
+				if tt.wantPanic == "" {
+					if r != nil {
+						t.Fatalf("MustNew() panicked: %v", r)
+					}
+					return
+				}
+				err, ok := r.(error)
+				if !ok || err.Error() != tt.wantPanic {
+					t.Errorf("MustNew() panic = %v, want %q", r, tt.wantPanic)
+				}
+			}()
+
+			b := MustNew(tt.initial, tt.factor, tt.max, WithJitter(false))
+			if got := b.Next(); got != tt.initial {
+				t.Errorf("Next() = %v, want %v", got, tt.initial)
+			}
+		})
+	}
+}
+
+func TestWithNoMaxCap(t *testing.T) {
+	tests := []struct {
+		name    string
+		max     time.Duration
+		opts    []Option
+		wantCap bool
+		want    []time.Duration
+	}{
+		{"tiny max clamps", 1 * time.Second, nil, true,
+			[]time.Duration{1 * time.Second, 1 * time.Second, 1 * time.Second, 1 * time.Second}},
+		{"no max cap", 1 * time.Second, []Option{WithNoMaxCap()}, false,
+			[]time.Duration{1 * time.Second, 2 * time.Second, 4 * time.Second, 8 * time.Second}},
+		{"zero max", 0, nil, false,
+			[]time.Duration{1 * time.Second, 2 * time.Second, 4 * time.Second, 8 * time.Second}},
+		{"largest duration", math.MaxInt64, nil, false,
+			[]time.Duration{1 * time.Second, 2 * time.Second, 4 * time.Second, 8 * time.Second}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := New(1*time.Second, 2.0, tt.max, append(tt.opts, WithJitter(false))...)
+			if got := b.HasMaxCap(); got != tt.wantCap {
+				t.Errorf("HasMaxCap() = %v, want %v", got, tt.wantCap)
+			}
+			if got := b.NextN(len(tt.want)); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("NextN() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	// growth saturates at the largest duration instead of overflowing
+	b := New(1*time.Second, 2.0, 1*time.Second, WithJitter(false), WithNoMaxCap())
+	for range 100 {
+		if d := b.Next(); d <= 0 {
+			t.Fatalf("Next() = %v, want positive", d)
+		}
+	}
+	if got := b.Current(); got != math.MaxInt64 {
+		t.Errorf("Current() after 100 calls = %v, want %v", got, time.Duration(math.MaxInt64))
+	}
+}
+
 func TestWithJitter(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -79,8 +548,8 @@ func TestWithJitter(t *testing.T) {
 			b := &Backoff{}
 			opt := WithJitter(tt.enabled)
 			opt(b)
-			if b.withJitter != tt.want {
-				t.Errorf("WithJitter() = %v, want %v", b.withJitter, tt.want)
+			if got := b.strategy != NoJitter; got != tt.want {
+				t.Errorf("WithJitter() = %v, want %v", got, tt.want)
 			}
 		})
 	}
@@ -179,9 +648,9 @@ func TestBackoff_Next(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			// Set a fixed seed for reproducible jitter tests
-			rand.Seed(42)
+			r := rand.New(rand.NewPCG(42, 0))
 
-			b := New(tt.initial, tt.factor, tt.max, WithJitter(tt.jitter))
+			b := New(tt.initial, tt.factor, tt.max, WithJitter(tt.jitter), WithRand(r))
 			var results []time.Duration
 
 			for i := 0; i < tt.calls; i++ {
@@ -193,66 +662,2321 @@ func TestBackoff_Next(t *testing.T) {
 	}
 }
 
-func TestBackoff_Reset(t *testing.T) {
-	b := New(100*time.Millisecond, 2.0, 1*time.Second, WithJitter(false))
-
-	// Call Next a few times to advance state
-	first := b.Next()
-	second := b.Next()
+func TestWithJitterStrategy(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []Option
+		want JitterStrategy
+	}{
+		{"default is full jitter", nil, FullJitter},
+		{"WithJitter(false) maps to NoJitter", []Option{WithJitter(false)}, NoJitter},
+		{"WithJitter(true) maps to FullJitter", []Option{WithJitter(true)}, FullJitter},
+		{"equal jitter", []Option{WithJitterStrategy(EqualJitter)}, EqualJitter},
+		{"decorrelated", []Option{WithJitterStrategy(Decorrelated)}, Decorrelated},
+		{"upward", []Option{WithJitterStrategy(UpwardJitter)}, UpwardJitter},
+		{"upward keeps strategy with factor", []Option{WithJitterStrategy(UpwardJitter), WithJitterFactor(0.2)}, UpwardJitter},
+		{"upward set after factor", []Option{WithJitterFactor(0.2), WithJitterStrategy(UpwardJitter)}, UpwardJitter},
+	}
 
-	// Verify progression
-	if first != 100*time.Millisecond {
-		t.Errorf("First Next() = %v, want %v", first, 100*time.Millisecond)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := New(100*time.Millisecond, 2.0, 1*time.Second, tt.opts...)
+			if b.strategy != tt.want {
+				t.Errorf("strategy = %v, want %v", b.strategy, tt.want)
+			}
+		})
 	}
-	if second != 200*time.Millisecond {
-		t.Errorf("Second Next() = %v, want %v", second, 200*time.Millisecond)
+}
+
+func TestWithRand(t *testing.T) {
+	run := func(seed int64) []time.Duration {
+		b := New(100*time.Millisecond, 2.0, 10*time.Second, WithRand(rand.New(rand.NewPCG(uint64(seed), 0))))
+		results := make([]time.Duration, 10)
+		for i := range results {
+			results[i] = b.Next()
+		}
+		return results
 	}
 
-	// Reset and verify it starts over
-	b.Reset()
-	afterReset := b.Next()
-	if afterReset != 100*time.Millisecond {
-		t.Errorf("Next() after Reset() = %v, want %v", afterReset, 100*time.Millisecond)
+	first := run(42)
+	second := run(42)
+	other := run(7)
+
+	same := true
+	for i := range first {
+		if first[i] != second[i] {
+			t.Errorf("Next() call %d not reproducible with same source: %v != %v", i+1, first[i], second[i])
+		}
+		if first[i] != other[i] {
+			same = false
+		}
+	}
+	if same {
+		t.Errorf("Next() produced identical sequences for different seeds")
 	}
 }
 
-func TestBackoff_Concurrency(t *testing.T) {
-	b := New(10*time.Millisecond, 1.5, 100*time.Millisecond, WithJitter(false))
-
-	const numGoroutines = 100
-	const callsPerGoroutine = 10
+func TestWithSeed(t *testing.T) {
+	strategies := []JitterStrategy{FullJitter, EqualJitter, Decorrelated}
 
-	var wg sync.WaitGroup
-	results := make([][]time.Duration, numGoroutines)
+	for _, strategy := range strategies {
+		t.Run(strategy.String(), func(t *testing.T) {
+			a := New(100*time.Millisecond, 2.0, 10*time.Second, WithJitterStrategy(strategy), WithSeed(42))
+			b := New(100*time.Millisecond, 2.0, 10*time.Second, WithJitterStrategy(strategy), WithSeed(42))
+			c := New(100*time.Millisecond, 2.0, 10*time.Second, WithJitterStrategy(strategy), WithSeed(43))
 
-	// Launch concurrent goroutines
-	for i := 0; i < numGoroutines; i++ {
-		wg.Add(1)
-		go func(index int) {
-			defer wg.Done()
-			for j := 0; j < callsPerGoroutine; j++ {
-				duration := b.Next()
-				results[index] = append(results[index], duration)
+			differs := false
+			for i := 0; i < 20; i++ {
+				da, db, dc := a.Next(), b.Next(), c.Next()
+				if da != db {
+					t.Errorf("Next() call %d with same seed: %v != %v", i+1, da, db)
+				}
+				if da != dc {
+					differs = true
+				}
 			}
-		}(i)
+			if !differs {
+				t.Errorf("different seeds produced identical sequences")
+			}
+		})
 	}
+}
 
-	wg.Wait()
+func TestWithCryptoJitter(t *testing.T) {
+	b := New(100*time.Millisecond, 2.0, 1*time.Second, WithCryptoJitter())
+	if !b.crypto {
+		t.Fatalf("WithCryptoJitter() should enable crypto jitter")
+	}
 
-	// Verify all results are valid (non-zero and within reasonable bounds)
-	for i, goroutineResults := range results {
-		for j, duration := range goroutineResults {
-			if duration <= 0 {
-				t.Errorf("Goroutine %d, call %d: got non-positive duration %v", i, j, duration)
-			}
-			if duration > 100*time.Millisecond {
-				t.Errorf("Goroutine %d, call %d: got duration %v exceeding max %v", i, j, duration, 100*time.Millisecond)
-			}
+	for i := 0; i < 100; i++ {
+		base := b.Peek()
+		if d := b.Next(); d < 0 || d > base {
+			t.Fatalf("Next() = %v, want range [0, %v]", d, base)
 		}
 	}
+
+	// Clones keep the crypto source
+	if c := b.Clone(); !c.crypto {
+		t.Errorf("Clone() lost crypto jitter")
+	}
+
+	// WithRand afterwards replaces it
+	if b := New(100*time.Millisecond, 2.0, 1*time.Second, WithCryptoJitter(), WithRand(nil)); b.crypto {
+		t.Errorf("WithRand() after WithCryptoJitter() should disable crypto jitter")
+	}
 }
 
-func TestBackoff_EdgeCases(t *testing.T) {
+func TestBackoff_EqualJitter(t *testing.T) {
+	b := New(100*time.Millisecond, 2.0, 1*time.Second, WithJitterStrategy(EqualJitter))
+
+	for i := 0; i < 1000; i++ {
+		base := b.Peek()
+		d := b.Next()
+		if d < base/2 || d > base {
+			t.Fatalf("Next() call %d = %v, want range [%v, %v]", i+1, d, base/2, base)
+		}
+	}
+}
+
+func TestBackoff_EstimateTotal(t *testing.T) {
+	const attempts = 5
+
+	tests := []struct {
+		name     string
+		strategy JitterStrategy
+	}{
+		{"full", FullJitter},
+		{"equal", EqualJitter},
+		{"decorrelated", Decorrelated},
+		{"none", NoJitter},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := New(100*time.Millisecond, 2.0, 10*time.Second, WithJitterStrategy(tt.strategy), WithSeed(42))
+			worst := b.TotalDelay(attempts)
+			if tt.strategy == Decorrelated {
+				// decorrelated draws up to 3x the previous delay, capped at max
+				worst = attempts * 10 * time.Second
+			}
+
+			p50, p99 := b.EstimateTotal(attempts, 10000)
+			if p99 < p50 {
+				t.Errorf("EstimateTotal() p99 %v < p50 %v", p99, p50)
+			}
+			if p50 <= 0 || p99 > worst {
+				t.Errorf("EstimateTotal() = (%v, %v), want within (0, %v]", p50, p99, worst)
+			}
+			if tt.strategy == NoJitter && (p50 != worst || p99 != worst) {
+				t.Errorf("EstimateTotal() without jitter = (%v, %v), want both %v", p50, p99, worst)
+			}
+			if tt.strategy == FullJitter {
+				// the sum of uniform draws centers on half the worst case
+				if diff := (p50 - worst/2).Abs(); diff > worst/20 {
+					t.Errorf("EstimateTotal() p50 = %v, want about %v", p50, worst/2)
+				}
+			}
+			if got := b.Attempt(); got != 0 {
+				t.Errorf("Attempt() after EstimateTotal() = %d, want 0", got)
+			}
+		})
+	}
+
+	b := New(100*time.Millisecond, 2.0, 10*time.Second)
+	if p50, p99 := b.EstimateTotal(0, 100); p50 != 0 || p99 != 0 {
+		t.Errorf("EstimateTotal(0, 100) = (%v, %v), want zeros", p50, p99)
+	}
+	if p50, p99 := b.EstimateTotal(3, 0); p50 != 0 || p99 != 0 {
+		t.Errorf("EstimateTotal(3, 0) = (%v, %v), want zeros", p50, p99)
+	}
+
+	// the injectable source makes estimates reproducible
+	x50, x99 := New(100*time.Millisecond, 2.0, 10*time.Second, WithSeed(7)).EstimateTotal(attempts, 1000)
+	y50, y99 := New(100*time.Millisecond, 2.0, 10*time.Second, WithSeed(7)).EstimateTotal(attempts, 1000)
+	if x50 != y50 || x99 != y99 {
+		t.Errorf("EstimateTotal() with same seed = (%v, %v) and (%v, %v), want equal", x50, x99, y50, y99)
+	}
+}
+
+func TestBackoff_SampleJitter(t *testing.T) {
+	const (
+		base = 1 * time.Second
+		n    = 10000
+	)
+
+	tests := []struct {
+		strategy JitterStrategy
+		lo, hi   time.Duration
+		wantMean time.Duration
+	}{
+		{FullJitter, 0, base, base / 2},
+		{EqualJitter, base / 2, base, base * 3 / 4},
+		{NoJitter, base, base, base},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.strategy.String(), func(t *testing.T) {
+			b := New(100*time.Millisecond, 2.0, 10*time.Second, WithJitterStrategy(tt.strategy), WithSeed(42))
+
+			s := b.SampleJitter(base, n)
+			if len(s) != n {
+				t.Fatalf("len(SampleJitter()) = %d, want %d", len(s), n)
+			}
+			var sum time.Duration
+			for _, d := range s {
+				if d < tt.lo || d > tt.hi {
+					t.Fatalf("SampleJitter() value %v, want range [%v, %v]", d, tt.lo, tt.hi)
+				}
+				sum += d
+			}
+			// within 2% of the expected mean
+			mean := sum / n
+			if diff := (mean - tt.wantMean).Abs(); diff > base/50 {
+				t.Errorf("mean of SampleJitter() = %v, want about %v", mean, tt.wantMean)
+			}
+
+			if got := b.Attempt(); got != 0 {
+				t.Errorf("Attempt() after SampleJitter() = %d, want 0", got)
+			}
+			if got := b.Peek(); got != 100*time.Millisecond {
+				t.Errorf("Peek() after SampleJitter() = %v, want %v", got, 100*time.Millisecond)
+			}
+		})
+	}
+
+	if s := New(1*time.Second, 2.0, 10*time.Second).SampleJitter(base, 0); s != nil {
+		t.Errorf("SampleJitter(base, 0) = %v, want nil", s)
+	}
+}
+
+func TestBackoff_UpwardJitter(t *testing.T) {
+	const max = 5 * time.Second
+	b := New(100*time.Millisecond, 2.0, max,
+		WithJitterStrategy(UpwardJitter), WithJitterFactor(0.5), WithSeed(42))
+
+	prevBase := time.Duration(0)
+	for i := 0; i < 1000; i++ {
+		if i%20 == 0 {
+			b.Reset()
+			prevBase = 0
+		}
+		d := b.Next()
+		base := b.Current()
+		if base < prevBase {
+			t.Fatalf("Current() after call %d = %v, smaller than previous %v", i+1, base, prevBase)
+		}
+		hi := min(base+base/2, max)
+		if d < base || d > hi {
+			t.Fatalf("Next() call %d = %v, want range [%v, %v]", i+1, d, base, hi)
+		}
+		prevBase = base
+	}
+}
+
+func TestWithRandomFactor(t *testing.T) {
+	const (
+		lo    = 1.8
+		hi    = 2.2
+		steps = 20
+	)
+	b := New(1*time.Second, 2.0, 0, WithJitter(false), WithRandomFactor(lo, hi), WithSeed(42))
+
+	b.Next()
+	prev := b.Current()
+	ratios := make(map[float64]bool)
+	for i := 1; i <= steps; i++ {
+		b.Next()
+		cur := b.Current()
+		// allow one nanosecond of truncation per step
+		r := float64(cur) / float64(prev)
+		if float64(cur) < float64(prev)*lo-1 || float64(cur) > float64(prev)*hi+1 {
+			t.Fatalf("step %d: %v -> %v, ratio %.4f outside [%v, %v]", i, prev, cur, r, lo, hi)
+		}
+		ratios[math.Round(r*1e4)] = true
+		prev = cur
+	}
+
+	total := float64(prev) / float64(time.Second)
+	if want := math.Pow(lo, steps); total < want*0.999 {
+		t.Errorf("growth after %d steps = %.1fx, want at least %.1fx", steps, total, want)
+	}
+	if want := math.Pow(hi, steps); total > want*1.001 {
+		t.Errorf("growth after %d steps = %.1fx, want at most %.1fx", steps, total, want)
+	}
+	if len(ratios) < 2 {
+		t.Errorf("factor was not randomized, ratios %v", ratios)
+	}
+
+	// Peek and Schedule report the worst case
+	if got, want := b.Peek(), time.Duration(float64(prev)*hi); got != want {
+		t.Errorf("Peek() = %v, want %v", got, want)
+	}
+}
+
+func TestWithRandomFactor_NextOKMatchesState(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []Option
+	}{
+		{"no total limit", nil},
+		{"total limit", []Option{WithMaxTotalDelay(time.Hour)}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := New(100*time.Millisecond, 1.0, 0, append(tt.opts,
+				WithJitter(false), WithRandomFactor(1, 3), WithSeed(1))...)
+			for i := range 10 {
+				d, ok := b.NextOK()
+				if !ok {
+					t.Fatalf("NextOK() %d = false, want true", i+1)
+				}
+				// the delay returned is the base left in the state
+				if got := b.Current(); got != d {
+					t.Fatalf("NextOK() %d = %v, but Current() = %v", i+1, d, got)
+				}
+			}
+		})
+	}
+}
+
+func TestWithRandomFactor_Validation(t *testing.T) {
+	tests := []struct {
+		name    string
+		lo, hi  float64
+		wantErr bool
+	}{
+		{"valid range", 1.8, 2.2, false},
+		{"single value", 2.0, 2.0, false},
+		{"min below 1", 0.5, 2.0, true},
+		{"max below min", 2.2, 1.8, true},
+		{"NaN min", math.NaN(), 2.0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewValidated(1*time.Second, 2.0, 10*time.Second, WithRandomFactor(tt.lo, tt.hi))
+			if (err != nil) != tt.wantErr {
+				t.Errorf("NewValidated() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			// New adjusts the range instead
+			b := New(1*time.Second, 2.0, 10*time.Second, WithRandomFactor(tt.lo, tt.hi))
+			if b.factorMin < 1 || b.factor < b.factorMin {
+				t.Errorf("New() range = [%v, %v], want 1 <= min <= max", b.factorMin, b.factor)
+			}
+		})
+	}
+}
+
+func TestWithFloor(t *testing.T) {
+	b := New(1*time.Second, 0.5, 1*time.Second, WithJitter(false), WithFloor(100*time.Millisecond))
+
+	want := []time.Duration{
+		1 * time.Second,
+		500 * time.Millisecond,
+		250 * time.Millisecond,
+		125 * time.Millisecond,
+		100 * time.Millisecond, // stops at the floor
+		100 * time.Millisecond,
+	}
+	for i, w := range want {
+		if got := b.Next(); got != w {
+			t.Errorf("Next() call %d = %v, want %v", i+1, got, w)
+		}
+	}
+	if got := b.Duration(10); got != 100*time.Millisecond {
+		t.Errorf("Duration(10) = %v, want %v", got, 100*time.Millisecond)
+	}
+}
+
+func TestWithFloor_Validation(t *testing.T) {
+	tests := []struct {
+		name       string
+		factor     float64
+		opts       []Option
+		wantErr    bool
+		wantFactor float64
+	}{
+		{"decay without floor", 0.5, nil, true, 1.0},
+		{"decay with floor", 0.5, []Option{WithFloor(100 * time.Millisecond)}, false, 0.5},
+		{"growth with floor", 2.0, []Option{WithFloor(100 * time.Millisecond)}, false, 2.0},
+		{"zero factor with floor", 0, []Option{WithFloor(100 * time.Millisecond)}, true, 1.0},
+		{"NaN factor with floor", math.NaN(), []Option{WithFloor(100 * time.Millisecond)}, true, 1.0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewValidated(1*time.Second, tt.factor, 1*time.Second, tt.opts...)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("NewValidated() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if b := New(1*time.Second, tt.factor, 1*time.Second, tt.opts...); b.factor != tt.wantFactor {
+				t.Errorf("New() factor = %v, want %v", b.factor, tt.wantFactor)
+			}
+		})
+	}
+}
+
+func TestWithMinDelay(t *testing.T) {
+	tests := []struct {
+		name      string
+		strategy  JitterStrategy
+		minDelay  time.Duration
+		max       time.Duration
+		wantFloor time.Duration
+	}{
+		{"full jitter", FullJitter, 30 * time.Millisecond, 1 * time.Second, 30 * time.Millisecond},
+		{"equal jitter", EqualJitter, 80 * time.Millisecond, 1 * time.Second, 80 * time.Millisecond},
+		{"no jitter", NoJitter, 150 * time.Millisecond, 1 * time.Second, 150 * time.Millisecond},
+		{"floor capped at max", FullJitter, 5 * time.Second, 1 * time.Second, 1 * time.Second},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := New(100*time.Millisecond, 2.0, tt.max,
+				WithJitterStrategy(tt.strategy), WithMinDelay(tt.minDelay))
+
+			for i := 0; i < 1000; i++ {
+				if i%10 == 0 {
+					b.Reset()
+				}
+				d := b.Next()
+				if d < tt.wantFloor {
+					t.Fatalf("Next() = %v, below floor %v", d, tt.wantFloor)
+				}
+				if d > tt.max {
+					t.Fatalf("Next() = %v, exceeds max %v", d, tt.max)
+				}
+			}
+		})
+	}
+}
+
+func TestWithSoftCap(t *testing.T) {
+	const ms = time.Millisecond
+
+	b := New(100*ms, 2.0, 5*time.Second, WithJitter(false), WithSoftCap(4*time.Second))
+	got := b.NextN(9)
+	want := []time.Duration{100 * ms, 200 * ms, 400 * ms, 800 * ms, 1600 * ms, 3200 * ms,
+		// above the soft cap each step covers part of the remaining distance
+		4375 * ms, 4687500 * time.Microsecond, 4843750 * time.Microsecond}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("NextN(9) = %v, want %v", got, want)
+	}
+
+	// eases toward max without jumping, and eventually reaches it
+	prev := got[len(got)-1]
+	for i := 0; prev < 5*time.Second; i++ {
+		if i > 100 {
+			t.Fatalf("Next() did not reach max, stuck at %v", prev)
+		}
+		d := b.Next()
+		if d <= prev || d > 5*time.Second {
+			t.Fatalf("Next() = %v after %v, want increasing up to %v", d, prev, 5*time.Second)
+		}
+		if d-prev > (5*time.Second-prev+1)/2+1 {
+			t.Fatalf("Next() jumped from %v to %v, want at most half the remaining distance", prev, d)
+		}
+		prev = d
+	}
+
+	// stateless methods follow the same curve
+	if got := b.Schedule(9); !reflect.DeepEqual(got, want) {
+		t.Errorf("Schedule(9) = %v, want %v", got, want)
+	}
+	if got := b.Duration(7); got != want[7] {
+		t.Errorf("Duration(7) = %v, want %v", got, want[7])
+	}
+
+	// a huge attempt index returns at once, saturated
+	for _, factor := range []float64{2.0, 1.001} {
+		huge := New(100*ms, factor, 5*time.Second, WithJitter(false), WithSoftCap(4*time.Second))
+		if got := huge.Duration(1 << 31); got != 5*time.Second {
+			t.Errorf("factor %v: Duration(1<<31) = %v, want %v", factor, got, 5*time.Second)
+		}
+		huge.ResetTo(math.MaxInt - 1)
+		if got := huge.Current(); got != 5*time.Second {
+			t.Errorf("factor %v: Current() after ResetTo(MaxInt-1) = %v, want %v", factor, got, 5*time.Second)
+		}
+	}
+	// factor 1.0 never grows, so the loop stops at once too
+	if got := New(100*ms, 1.0, 5*time.Second, WithJitter(false), WithSoftCap(4*time.Second)).Duration(1 << 31); got != 100*ms {
+		t.Errorf("factor 1.0: Duration(1<<31) = %v, want %v", got, 100*ms)
+	}
+
+	// without a soft cap the delay jumps straight to max
+	plain := New(100*ms, 2.0, 5*time.Second, WithJitter(false))
+	if got := plain.NextN(7)[6]; got != 5*time.Second {
+		t.Errorf("seventh Next() without soft cap = %v, want %v", got, 5*time.Second)
+	}
+}
+
+func TestWithSoftCap_Validation(t *testing.T) {
+	tests := []struct {
+		name string
+		max  time.Duration
+		soft time.Duration
+	}{
+		{"negative", 5 * time.Second, -1},
+		{"equal to max", 5 * time.Second, 5 * time.Second},
+		{"above max", 5 * time.Second, 6 * time.Second},
+		{"unbounded max", 0, 4 * time.Second},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewValidated(1*time.Second, 2.0, tt.max, WithSoftCap(tt.soft)); err == nil {
+				t.Errorf("NewValidated() with soft cap %v and max %v should fail", tt.soft, tt.max)
+			}
+			// New ignores it
+			b := New(1*time.Second, 2.0, tt.max, WithJitter(false), WithSoftCap(tt.soft))
+			if got := b.NextN(4)[3]; got != min(8*time.Second, b.maxDelay()) {
+				t.Errorf("fourth Next() = %v, want the plain curve", got)
+			}
+		})
+	}
+}
+
+func TestWithJitterFactor(t *testing.T) {
+	tests := []struct {
+		name    string
+		f       float64
+		initial time.Duration
+		max     time.Duration
+		wantLo  time.Duration
+		wantHi  time.Duration
+	}{
+		{"20 percent", 0.2, 1 * time.Second, 10 * time.Second, 800 * time.Millisecond, 1200 * time.Millisecond},
+		{"full window", 1.0, 1 * time.Second, 10 * time.Second, 0, 2 * time.Second},
+		{"capped at max", 0.5, 1 * time.Second, 1200 * time.Millisecond, 500 * time.Millisecond, 1200 * time.Millisecond},
+		{"zero is no jitter", 0, 1 * time.Second, 10 * time.Second, 1 * time.Second, 1 * time.Second},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := New(tt.initial, 2.0, tt.max, WithJitterFactor(tt.f))
+			for i := 0; i < 1000; i++ {
+				if got := b.Duration(0); got < tt.wantLo || got > tt.wantHi {
+					t.Fatalf("Duration(0) = %v, want range [%v, %v]", got, tt.wantLo, tt.wantHi)
+				}
+			}
+		})
+	}
+
+	if b := New(1*time.Second, 2.0, 10*time.Second, WithJitterFactor(0)); b.strategy != NoJitter {
+		t.Errorf("WithJitterFactor(0) strategy = %v, want NoJitter", b.strategy)
+	}
+}
+
+func TestWithJitterFactor_Validation(t *testing.T) {
+	for _, f := range []float64{-0.1, 1.5, math.NaN()} {
+		if _, err := NewValidated(1*time.Second, 2.0, 10*time.Second, WithJitterFactor(f)); err == nil {
+			t.Errorf("NewValidated() with jitter factor %v should fail", f)
+		}
+		b := New(1*time.Second, 2.0, 10*time.Second, WithJitterFactor(f))
+		if b.jitterFrac < 0 || b.jitterFrac > 1 {
+			t.Errorf("New() with jitter factor %v: jitterFrac = %v, want clamped to [0, 1]", f, b.jitterFrac)
+		}
+	}
+}
+
+func TestWithJitterWindow(t *testing.T) {
+	const ms = time.Millisecond
+
+	tests := []struct {
+		name           string
+		initial, max   time.Duration
+		window         time.Duration
+		wantLo, wantHi time.Duration
+		clamped        time.Duration // a bound reached by clamping, hit repeatedly
+	}{
+		{"within bounds", 1000 * ms, 10 * time.Second, 250 * ms, 750 * ms, 1250 * ms, -1},
+		{"clamped at zero", 100 * ms, 10 * time.Second, 250 * ms, 0, 350 * ms, 0},
+		{"clamped at max", 1000 * ms, 1100 * ms, 250 * ms, 750 * ms, 1100 * ms, 1100 * ms},
+		{"huge window", 1000 * ms, 0, math.MaxInt64, 0, math.MaxInt64, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := New(tt.initial, 2.0, tt.max, WithJitterWindow(tt.window), WithSeed(42))
+			hits := 0
+			for range 1000 {
+				got := b.Duration(0)
+				if got < tt.wantLo || got > tt.wantHi {
+					t.Fatalf("Duration(0) = %v, want range [%v, %v]", got, tt.wantLo, tt.wantHi)
+				}
+				if got == tt.clamped {
+					hits++
+				}
+			}
+			if tt.clamped >= 0 && hits < 100 {
+				t.Errorf("Duration(0) hit the clamped bound %v %d times, want at least 100", tt.clamped, hits)
+			}
+		})
+	}
+
+	if _, err := NewValidated(1*time.Second, 2.0, 10*time.Second, WithJitterWindow(-1)); err == nil {
+		t.Errorf("NewValidated() with negative jitter window should fail")
+	}
+	if b := New(1*time.Second, 2.0, 10*time.Second, WithJitter(false), WithJitterWindow(-1)); b.Next() != 1*time.Second {
+		t.Errorf("New() with negative jitter window should ignore it")
+	}
+}
+
+func TestApplyJitter(t *testing.T) {
+	const base = 1 * time.Second
+
+	tests := []struct {
+		strategy JitterStrategy
+		lo, hi   time.Duration
+	}{
+		{FullJitter, 0, base},
+		{EqualJitter, base / 2, base},
+		{Decorrelated, base, 3 * base},
+		{NoJitter, base, base},
+		{ProportionalJitter, base, base},
+		{UpwardJitter, base, base},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.strategy.String(), func(t *testing.T) {
+			for seed := range uint64(100) {
+				got := ApplyJitter(base, tt.strategy, rand.New(rand.NewPCG(seed, 0)))
+				if got < tt.lo || got > tt.hi {
+					t.Fatalf("ApplyJitter() = %v, want range [%v, %v]", got, tt.lo, tt.hi)
+				}
+
+				// same source, same base: the first Next() draws the same value
+				b := New(base, 2.0, 1*time.Hour, WithJitterStrategy(tt.strategy), WithRand(rand.New(rand.NewPCG(seed, 0))))
+				if want := b.Next(); got != want {
+					t.Fatalf("seed %d: ApplyJitter() = %v, want %v as Next()", seed, got, want)
+				}
+			}
+
+			if got := ApplyJitter(base, tt.strategy, nil); got < tt.lo || got > tt.hi {
+				t.Errorf("ApplyJitter() with global source = %v, want range [%v, %v]", got, tt.lo, tt.hi)
+			}
+		})
+	}
+
+	if got := ApplyJitter(-1, FullJitter, nil); got != 0 {
+		t.Errorf("ApplyJitter() with negative base = %v, want 0", got)
+	}
+}
+
+func TestBackoff_DecorrelatedJitter(t *testing.T) {
+	const (
+		initial = 100 * time.Millisecond
+		max     = 2 * time.Second
+		calls   = 50
+	)
+
+	run := func() []time.Duration {
+		r := rand.New(rand.NewPCG(42, 0))
+		b := New(initial, 2.0, max, WithJitterStrategy(Decorrelated), WithRand(r))
+		results := make([]time.Duration, calls)
+		for i := range results {
+			results[i] = b.Next()
+		}
+		return results
+	}
+
+	first := run()
+	second := run()
+
+	for i, d := range first {
+		if d < initial || d > max {
+			t.Errorf("Next() call %d = %v, want range [%v, %v]", i+1, d, initial, max)
+		}
+		if d != second[i] {
+			t.Errorf("Next() call %d not deterministic with same seed: %v != %v", i+1, d, second[i])
+		}
+	}
+
+	// First call draws from [initial, 3*initial]
+	if first[0] > 3*initial {
+		t.Errorf("Next() call 1 = %v, want at most %v", first[0], 3*initial)
+	}
+}
+
+func TestBackoff_NextN(t *testing.T) {
+	newSeeded := func() *Backoff {
+		return New(100*time.Millisecond, 2.0, 10*time.Second, WithRand(rand.New(rand.NewPCG(42, 0))))
+	}
+
+	batch := newSeeded()
+	got := batch.NextN(3)
+
+	single := newSeeded()
+	for i := 0; i < 3; i++ {
+		if want := single.Next(); got[i] != want {
+			t.Errorf("NextN(3)[%d] = %v, want %v", i, got[i], want)
+		}
+	}
+
+	if batch.Attempt() != 3 || batch.Peek() != single.Peek() {
+		t.Errorf("NextN(3) left attempt=%d peek=%v, want attempt=3 peek=%v",
+			batch.Attempt(), batch.Peek(), single.Peek())
+	}
+
+	if got := batch.NextN(0); got != nil {
+		t.Errorf("NextN(0) = %v, want nil", got)
+	}
+}
+
+func TestBackoff_NextAfter(t *testing.T) {
+	tests := []struct {
+		name string
+		hint time.Duration
+		want []time.Duration
+	}{
+		{
+			name: "hint below computed delay",
+			hint: 50 * time.Millisecond,
+			want: []time.Duration{100 * time.Millisecond, 200 * time.Millisecond},
+		},
+		{
+			name: "hint above computed delay",
+			hint: 150 * time.Millisecond,
+			want: []time.Duration{150 * time.Millisecond, 200 * time.Millisecond},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := New(100*time.Millisecond, 2.0, 1*time.Second, WithJitter(false))
+			for i, want := range tt.want {
+				if got := b.NextAfter(tt.hint); got != want {
+					t.Errorf("NextAfter() call %d = %v, want %v", i+1, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestBackoff_Schedule(t *testing.T) {
+	tests := []struct {
+		name   string
+		jitter bool
+		n      int
+		want   []time.Duration
+	}{
+		{
+			name:   "grows up to max",
+			jitter: false,
+			n:      5,
+			want: []time.Duration{
+				100 * time.Millisecond,
+				200 * time.Millisecond,
+				400 * time.Millisecond,
+				500 * time.Millisecond,
+				500 * time.Millisecond,
+			},
+		},
+		{
+			name:   "jitter returns deterministic base",
+			jitter: true,
+			n:      3,
+			want: []time.Duration{
+				100 * time.Millisecond,
+				200 * time.Millisecond,
+				400 * time.Millisecond,
+			},
+		},
+		{
+			name: "zero length",
+			n:    0,
+			want: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := New(100*time.Millisecond, 2.0, 500*time.Millisecond, WithJitter(tt.jitter))
+			b.Next()
+
+			got := b.Schedule(tt.n)
+			if len(got) != len(tt.want) {
+				t.Fatalf("Schedule(%d) returned %d values, want %d", tt.n, len(got), len(tt.want))
+			}
+			for i, want := range tt.want {
+				if got[i] != want {
+					t.Errorf("Schedule(%d)[%d] = %v, want %v", tt.n, i, got[i], want)
+				}
+			}
+
+			// Live state must be untouched
+			if got := b.Attempt(); got != 1 {
+				t.Errorf("Attempt() after Schedule() = %d, want 1", got)
+			}
+			if got := b.Peek(); got != 200*time.Millisecond {
+				t.Errorf("Peek() after Schedule() = %v, want %v", got, 200*time.Millisecond)
+			}
+		})
+	}
+}
+
+func TestBackoff_Drain(t *testing.T) {
+	const ms = time.Millisecond
+
+	tests := []struct {
+		name  string
+		opts  []Option
+		calls int // NextOK calls before Drain
+		want  []time.Duration
+	}{
+		{"fresh", nil, 0, []time.Duration{100 * ms, 200 * ms, 400 * ms, 800 * ms, 1000 * ms}},
+		{"midway", nil, 2, []time.Duration{400 * ms, 800 * ms, 1000 * ms}},
+		{"last attempt", nil, 4, []time.Duration{1000 * ms}},
+		{"exhausted", nil, 5, nil},
+		{"first delay", []Option{WithFirstDelay(10 * ms)}, 0, []time.Duration{10 * ms, 100 * ms, 200 * ms, 400 * ms, 800 * ms}},
+		{"first delay taken", []Option{WithFirstDelay(10 * ms)}, 1, []time.Duration{100 * ms, 200 * ms, 400 * ms, 800 * ms}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := New(100*ms, 2.0, 1*time.Second, append(tt.opts, WithMaxAttempts(5))...)
+			for range tt.calls {
+				b.NextOK()
+			}
+			got := b.Drain()
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Drain() = %v, want %v", got, tt.want)
+			}
+			if _, ok := b.NextOK(); ok {
+				t.Error("NextOK() after Drain() = true, want false")
+			}
+			if got := b.Remaining(); got != 0 {
+				t.Errorf("Remaining() after Drain() = %d, want 0", got)
+			}
+			if got := b.Drain(); len(got) != 0 {
+				t.Errorf("second Drain() = %v, want empty", got)
+			}
+		})
+	}
+
+	// saturated and constant delays are all listed
+	if got, want := New(1*time.Second, 2.0, 4*time.Second, WithJitter(false), WithMaxAttempts(6)).Drain(),
+		[]time.Duration{1 * time.Second, 2 * time.Second, 4 * time.Second, 4 * time.Second, 4 * time.Second, 4 * time.Second}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Drain() at the cap = %v, want %v", got, want)
+	}
+	if got, want := Constant(time.Second, WithMaxAttempts(5)).Drain(), slices.Repeat([]time.Duration{time.Second}, 5); !reflect.DeepEqual(got, want) {
+		t.Errorf("Constant Drain() = %v, want %v", got, want)
+	}
+
+	// a huge limit is capped in length, keeping the start of the schedule
+	for _, limit := range []int{math.MaxInt, 1 << 40} {
+		b := New(100*ms, 2.0, 1*time.Second, WithMaxAttempts(limit))
+		b.NextOK()
+		got := b.Drain()
+		if len(got) != drainLimit {
+			t.Fatalf("len(Drain()) with limit %d = %d, want %d", limit, len(got), drainLimit)
+		}
+		want := []time.Duration{200 * ms, 400 * ms, 800 * ms, 1000 * ms, 1000 * ms}
+		if !reflect.DeepEqual(got[:len(want)], want) || got[len(got)-1] != 1000*ms {
+			t.Errorf("Drain() with limit %d = %v ... %v, want %v ... 1s", limit, got[:len(want)], got[len(got)-1], want)
+		}
+		if _, ok := b.NextOK(); ok {
+			t.Errorf("NextOK() after Drain() with limit %d = true, want false", limit)
+		}
+	}
+
+	// unlimited: nothing to drain and the policy keeps going
+	b := New(100*ms, 2.0, 1*time.Second, WithJitter(false))
+	b.Next()
+	if got := b.Drain(); len(got) != 0 {
+		t.Errorf("Drain() when unlimited = %v, want empty", got)
+	}
+	if d, ok := b.NextOK(); !ok || d != 200*ms {
+		t.Errorf("NextOK() after Drain() when unlimited = %v, %v, want %v, true", d, ok, 200*ms)
+	}
+}
+
+func TestBackoff_Remaining(t *testing.T) {
+	b := New(10*time.Millisecond, 2.0, 1*time.Second, WithMaxAttempts(3))
+
+	for want := 3; want >= 0; want-- {
+		if got := b.Remaining(); got != want {
+			t.Errorf("Remaining() = %d, want %d", got, want)
+		}
+		if got := b.Attempt(); got != 3-want {
+			t.Errorf("Attempt() = %d, want %d", got, 3-want)
+		}
+		b.NextOK()
+	}
+	if got := b.Remaining(); got != 0 {
+		t.Errorf("Remaining() after exhaustion = %d, want 0", got)
+	}
+	// Next does not honor the limit, but Remaining never goes negative
+	b.Next()
+	if got := b.Remaining(); got != 0 {
+		t.Errorf("Remaining() after extra Next() = %d, want 0", got)
+	}
+
+	b.Reset()
+	if got := b.Remaining(); got != 3 {
+		t.Errorf("Remaining() after Reset() = %d, want 3", got)
+	}
+
+	if got := New(10*time.Millisecond, 2.0, 1*time.Second).Remaining(); got != -1 {
+		t.Errorf("Remaining() when unlimited = %d, want -1", got)
+	}
+}
+
+func TestBackoff_MaxElapsedTime(t *testing.T) {
+	clk := newFakeClock()
+	b := New(1*time.Millisecond, 1.0, 1*time.Millisecond,
+		WithJitter(false), WithMaxElapsedTime(20*time.Millisecond), WithClock(clk))
+
+	if b.Stop() {
+		t.Errorf("Stop() before first Next() = true, want false")
+	}
+	if _, ok := b.NextOK(); !ok {
+		t.Fatalf("NextOK() on first call = false, want true")
+	}
+	if b.Stop() {
+		t.Errorf("Stop() right after first Next() = true, want false")
+	}
+
+	clk.Advance(30 * time.Millisecond)
+	if !b.Stop() {
+		t.Errorf("Stop() after budget = false, want true")
+	}
+	if _, ok := b.NextOK(); ok {
+		t.Errorf("NextOK() after budget = true, want false")
+	}
+
+	// Reset restarts the budget
+	b.Reset()
+	if b.Stop() {
+		t.Errorf("Stop() after Reset() = true, want false")
+	}
+	if _, ok := b.NextOK(); !ok {
+		t.Errorf("NextOK() after Reset() = false, want true")
+	}
+}
+
+func TestWithInitialJitterSpread(t *testing.T) {
+	const (
+		initial = 100 * time.Millisecond
+		spread  = 50 * time.Millisecond
+	)
+
+	for seed := range uint64(20) {
+		b := New(initial, 2.0, 10*time.Second, WithJitter(false),
+			WithInitialJitterSpread(spread), WithRand(rand.New(rand.NewPCG(seed, 0))))
+
+		for range 2 {
+			if d := b.Next(); d < initial || d > initial+spread {
+				t.Fatalf("seed %d: first Next() = %v, want in [%v, %v]", seed, d, initial, initial+spread)
+			}
+			if d := b.Next(); d != 2*initial {
+				t.Errorf("seed %d: second Next() = %v, want %v", seed, d, 2*initial)
+			}
+			// Reset starts a new sequence, spread again
+			b.Reset()
+		}
+	}
+
+	b := New(initial, 2.0, 10*time.Second, WithJitter(false))
+	if d := b.Next(); d != initial {
+		t.Errorf("first Next() without spread = %v, want %v", d, initial)
+	}
+}
+
+func TestWithStrictMax(t *testing.T) {
+	const limit = 1 * time.Second
+
+	strategies := []JitterStrategy{FullJitter, EqualJitter, Decorrelated, NoJitter, ProportionalJitter, UpwardJitter}
+
+	tests := []struct {
+		name   string
+		opts   []Option
+		exceed bool // whether some delay may exceed limit
+	}{
+		{"jitter at the cap", nil, false},
+		{"strict at the cap", []Option{WithStrictMax(true)}, false},
+		{"spread without strict", []Option{WithInitialJitterSpread(limit)}, true},
+		{"spread with strict", []Option{WithInitialJitterSpread(limit), WithStrictMax(true)}, false},
+	}
+
+	for _, tt := range tests {
+		for _, strategy := range strategies {
+			t.Run(tt.name+"/"+strategy.String(), func(t *testing.T) {
+				exceeded := false
+				for seed := range uint64(50) {
+					// initial == limit: the base sits at the cap from the first call
+					b := New(limit, 2.0, limit, append(tt.opts, WithJitterStrategy(strategy),
+						WithJitterFactor(0.5), WithRand(rand.New(rand.NewPCG(seed, 0))))...)
+					for range 3 {
+						d, ok := b.NextOK()
+						if !ok {
+							t.Fatalf("NextOK() = false, want true")
+						}
+						if d > limit {
+							exceeded = true
+							if !tt.exceed {
+								t.Fatalf("seed %d: NextOK() = %v, want at most %v", seed, d, limit)
+							}
+						}
+					}
+				}
+				if tt.exceed && !exceeded {
+					t.Errorf("no delay exceeded %v, want the initial spread to exceed it", limit)
+				}
+			})
+		}
+	}
+}
+
+func TestWithGrowthFromAttempt(t *testing.T) {
+	const (
+		initial = 100 * time.Millisecond
+		limit   = 1 * time.Hour
+	)
+
+	t.Run("no jitter", func(t *testing.T) {
+		// without jitter the returned value is the base, so both modes agree
+		a := New(initial, 2.0, limit, WithJitter(false))
+		f := New(initial, 2.0, limit, WithJitter(false), WithGrowthFromAttempt(false))
+		if got, want := f.NextN(10), a.NextN(10); !reflect.DeepEqual(got, want) {
+			t.Errorf("feedback NextN() = %v, want %v", got, want)
+		}
+	})
+
+	for seed := range uint64(20) {
+		attempt := New(initial, 2.0, limit, WithGrowthFromAttempt(true), WithRand(rand.New(rand.NewPCG(seed, 0))))
+		feedback := New(initial, 2.0, limit, WithGrowthFromAttempt(false), WithRand(rand.New(rand.NewPCG(seed, 0))))
+
+		diverged := false
+		prev := time.Duration(0)
+		for n := range 12 {
+			a, f := attempt.Next(), feedback.Next()
+			if a != f {
+				diverged = true
+			}
+			// attempt mode: the window depends only on the attempt index
+			if hi := initial << n; a > hi {
+				t.Fatalf("seed %d: attempt mode call %d = %v, want at most %v", seed, n+1, a, hi)
+			}
+			// feedback mode: the window grows from the last returned value
+			if hi := max(prev, initial) * 2; n > 0 && f > hi {
+				t.Fatalf("seed %d: feedback mode call %d = %v, want at most %v", seed, n+1, f, hi)
+			}
+			if got, want := feedback.Current(), max(prev, initial)*2; n > 0 && got != want {
+				t.Fatalf("seed %d: feedback Current() after call %d = %v, want %v", seed, n+1, got, want)
+			}
+			prev = f
+		}
+		if !diverged {
+			t.Errorf("seed %d: modes returned the same sequence, want them to diverge", seed)
+		}
+		if got, want := attempt.Current(), initial<<11; got != want {
+			t.Errorf("seed %d: attempt Current() = %v, want %v", seed, got, want)
+		}
+	}
+}
+
+func TestWithRounding(t *testing.T) {
+	const ms = time.Millisecond
+
+	tests := []struct {
+		name        string
+		initial     time.Duration
+		max         time.Duration
+		floor       time.Duration
+		granularity time.Duration
+		want        time.Duration
+	}{
+		{"rounds up", 137 * ms, 1 * time.Second, 0, 10 * ms, 140 * ms},
+		{"rounds down", 134 * ms, 1 * time.Second, 0, 10 * ms, 130 * ms},
+		{"half rounds up", 135 * ms, 1 * time.Second, 0, 10 * ms, 140 * ms},
+		{"already a multiple", 140 * ms, 1 * time.Second, 0, 10 * ms, 140 * ms},
+		{"never above max", 137 * ms, 138 * ms, 0, 10 * ms, 130 * ms},
+		{"max itself a multiple", 137 * ms, 140 * ms, 0, 10 * ms, 140 * ms},
+		{"zero granularity", 137 * ms, 1 * time.Second, 0, 0, 137 * ms},
+		{"coarse granularity", 1400 * ms, 10 * time.Second, 0, time.Second, 1 * time.Second},
+		{"never below min delay", 137 * ms, 1 * time.Second, 137 * ms, 100 * ms, 200 * ms},
+		{"min delay below the rounded value", 134 * ms, 1 * time.Second, 120 * ms, 10 * ms, 130 * ms},
+		{"no multiple between min delay and max", 137 * ms, 150 * ms, 137 * ms, 100 * ms, 137 * ms},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := New(tt.initial, 1.0, tt.max, WithJitter(false), WithMinDelay(tt.floor), WithRounding(tt.granularity))
+			if got := b.Next(); got != tt.want {
+				t.Errorf("Next() = %v, want %v", got, tt.want)
+			}
+			// the locked path rounds the same way
+			b = New(tt.initial, 1.0, tt.max, WithJitter(false), WithMinDelay(tt.floor), WithRounding(tt.granularity),
+				WithMaxTotalDelay(time.Hour))
+			if got, _ := b.NextOK(); got != tt.want {
+				t.Errorf("NextOK() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	// jittered delays land on multiples and stay within the floor and the cap
+	const (
+		floor = 133 * ms
+		limit = 995 * ms
+	)
+	for _, strategy := range []JitterStrategy{FullJitter, EqualJitter, Decorrelated, NoJitter} {
+		t.Run("jitter/"+strategy.String(), func(t *testing.T) {
+			b := New(137*ms, 2.0, limit, WithJitterStrategy(strategy), WithMinDelay(floor), WithRounding(10*ms),
+				WithRand(rand.New(rand.NewPCG(1, 2))))
+			for i := range 50 {
+				d := b.Next()
+				if d%(10*ms) != 0 {
+					t.Fatalf("call %d = %v, want a multiple of 10ms", i+1, d)
+				}
+				if d < floor || d > limit {
+					t.Fatalf("call %d = %v, want within [%v, %v]", i+1, d, floor, limit)
+				}
+			}
+		})
+	}
+}
+
+func TestWithFirstDelay(t *testing.T) {
+	const ms = time.Millisecond
+	want := []time.Duration{10 * ms, 100 * ms, 200 * ms, 400 * ms, 800 * ms, 1000 * ms}
+
+	b := New(100*ms, 2.0, 1*time.Second, WithJitter(false), WithFirstDelay(10*ms))
+	if got := b.Peek(); got != want[0] {
+		t.Errorf("Peek() before first Next() = %v, want %v", got, want[0])
+	}
+	for range 2 {
+		if got := b.NextN(len(want)); !reflect.DeepEqual(got, want) {
+			t.Errorf("NextN() = %v, want %v", got, want)
+		}
+		// Reset brings the quick first retry back
+		b.Reset()
+	}
+
+	// the transition from first to second delay
+	b.Next()
+	if got := b.Current(); got != 10*ms {
+		t.Errorf("Current() after first Next() = %v, want %v", got, 10*ms)
+	}
+	if got := b.Peek(); got != 100*ms {
+		t.Errorf("Peek() after first Next() = %v, want %v", got, 100*ms)
+	}
+	if got := b.Next(); got != 100*ms {
+		t.Errorf("second Next() = %v, want %v", got, 100*ms)
+	}
+
+	if got := b.Schedule(len(want)); !reflect.DeepEqual(got, want) {
+		t.Errorf("Schedule() = %v, want %v", got, want)
+	}
+	for n, w := range want {
+		if got := b.Duration(n); got != w {
+			t.Errorf("Duration(%d) = %v, want %v", n, got, w)
+		}
+	}
+
+	b.ResetTo(0)
+	if got := b.Next(); got != 100*ms {
+		t.Errorf("Next() after ResetTo(0) = %v, want %v", got, 100*ms)
+	}
+}
+
+func TestWithImmediateFirst(t *testing.T) {
+	const ms = time.Millisecond
+	want := []time.Duration{0, 100 * ms, 200 * ms, 400 * ms, 800 * ms, 1000 * ms}
+
+	b := New(100*ms, 2.0, 1*time.Second, WithJitter(false), WithImmediateFirst())
+	for range 2 {
+		if got := b.NextN(len(want)); !reflect.DeepEqual(got, want) {
+			t.Errorf("NextN() = %v, want %v", got, want)
+		}
+		// Reset brings the immediate probe back
+		b.Reset()
+	}
+	if got := b.Schedule(len(want)); !reflect.DeepEqual(got, want) {
+		t.Errorf("Schedule() = %v, want %v", got, want)
+	}
+
+	// the first call is zero whatever would otherwise shift it
+	tests := []struct {
+		name string
+		opts []Option
+		next func(b *Backoff) time.Duration
+	}{
+		{"full jitter", []Option{WithJitterStrategy(FullJitter)}, (*Backoff).Next},
+		{"min delay", []Option{WithMinDelay(50 * ms)}, (*Backoff).Next},
+		{"initial spread", []Option{WithInitialJitterSpread(time.Second)}, (*Backoff).Next},
+		{"NextOK with total limit", []Option{WithMaxTotalDelay(time.Hour)}, func(b *Backoff) time.Duration {
+			d, _ := b.NextOK()
+			return d
+		}},
+		{"overrides WithFirstDelay", []Option{WithFirstDelay(10 * ms), WithImmediateFirst()}, (*Backoff).Next},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := New(100*ms, 2.0, 1*time.Second, append([]Option{WithJitter(false), WithImmediateFirst()}, tt.opts...)...)
+			for range 50 {
+				if got := tt.next(b); got != 0 {
+					t.Fatalf("first call = %v, want 0", got)
+				}
+				if got := tt.next(b); got <= 0 || got > 1100*ms {
+					t.Fatalf("second call = %v, want about initial", got)
+				}
+				b.Reset()
+			}
+		})
+	}
+
+	// WithFirstDelay given later takes over
+	b = New(100*ms, 2.0, 1*time.Second, WithJitter(false), WithImmediateFirst(), WithFirstDelay(10*ms))
+	if got := b.Next(); got != 10*ms {
+		t.Errorf("Next() with WithFirstDelay after WithImmediateFirst = %v, want %v", got, 10*ms)
+	}
+}
+
+func TestWithMaxInterval(t *testing.T) {
+	const limit = 500 * time.Millisecond
+
+	tests := []struct {
+		name string
+		opts []Option
+		next func(b *Backoff) time.Duration
+	}{
+		{"Next", []Option{WithJitter(false)}, (*Backoff).Next},
+		{"NextOK", []Option{WithJitter(false)}, func(b *Backoff) time.Duration {
+			d, _ := b.NextOK()
+			return d
+		}},
+		{"full jitter", nil, (*Backoff).Next},
+		{"equal jitter", []Option{WithJitterStrategy(EqualJitter)}, (*Backoff).Next},
+		{"upward jitter", []Option{WithJitterStrategy(UpwardJitter), WithJitterFactor(0.5)}, (*Backoff).Next},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// initial above the cap: the first call is clamped too
+			b := New(1*time.Second, 2.0, 0, append(tt.opts, WithMaxInterval(limit))...)
+			for i := range 20 {
+				if got := tt.next(b); got > limit {
+					t.Fatalf("call %d = %v, want at most %v", i, got, limit)
+				}
+			}
+		})
+	}
+
+	b := New(1*time.Second, 2.0, 0, WithJitter(false), WithMaxInterval(limit))
+	if got := b.Next(); got != limit {
+		t.Errorf("first Next() = %v, want %v", got, limit)
+	}
+	if got := b.Config().Max; got != limit {
+		t.Errorf("Config().Max = %v, want %v", got, limit)
+	}
+
+	// New stays lenient: the first call may exceed max
+	b = New(1*time.Second, 2.0, limit, WithJitter(false))
+	if got := b.Next(); got != 1*time.Second {
+		t.Errorf("first Next() without WithMaxInterval = %v, want %v", got, 1*time.Second)
+	}
+}
+
+func TestWithFirstJitterFloor(t *testing.T) {
+	const initial = 1 * time.Second
+
+	tests := []struct {
+		name  string
+		opts  []Option
+		first func(b *Backoff) time.Duration
+	}{
+		{"Next", nil, (*Backoff).Next},
+		{"NextOK with total limit", []Option{WithMaxTotalDelay(time.Hour)}, func(b *Backoff) time.Duration {
+			d, _ := b.NextOK()
+			return d
+		}},
+		{"Duration", nil, func(b *Backoff) time.Duration { return b.Duration(0) }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var belowHalf, belowHalfWithout bool
+			for seed := range uint64(200) {
+				b := New(initial, 2.0, 10*time.Second, append(tt.opts, WithFirstJitterFloor(true),
+					WithRand(rand.New(rand.NewPCG(seed, 0))))...)
+				for range 2 {
+					if d := tt.first(b); d < initial/2 || d > initial {
+						t.Fatalf("seed %d: first delay = %v, want in [%v, %v]", seed, d, initial/2, initial)
+					}
+					// later attempts keep full jitter
+					if d := b.Next(); d < initial {
+						belowHalf = true
+					}
+					b.Reset()
+				}
+
+				plain := New(initial, 2.0, 10*time.Second, append(tt.opts, WithRand(rand.New(rand.NewPCG(seed, 0))))...)
+				if tt.first(plain) < initial/2 {
+					belowHalfWithout = true
+				}
+			}
+			if !belowHalf {
+				t.Errorf("second delay never below %v, want full jitter after the first attempt", initial)
+			}
+			if !belowHalfWithout {
+				t.Errorf("first delay without the option never below %v, want full jitter", initial/2)
+			}
+		})
+	}
+}
+
+func TestWithName(t *testing.T) {
+	var names []string
+	var b *Backoff
+	b = New(100*time.Millisecond, 2.0, 200*time.Millisecond, WithName("cache"),
+		WithObserver(func(int, time.Duration) { names = append(names, b.Name()) }),
+		WithOnMax(func() { names = append(names, "max:"+b.Name()) }))
+
+	b.Next()
+	b.Next()
+	if want := []string{"cache", "max:cache", "cache"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("names seen by callbacks = %q, want %q", names, want)
+	}
+
+	// one named observer shared by several backoffs tells them apart
+	type event struct {
+		name    string
+		attempt int
+		delay   time.Duration
+	}
+	var events []event
+	shared := WithNamedObserver(func(name string, attempt int, delay time.Duration) {
+		events = append(events, event{name, attempt, delay})
+	})
+	db := New(100*time.Millisecond, 2.0, time.Second, WithJitter(false), WithName("db"), shared)
+	cache := New(10*time.Millisecond, 2.0, time.Second, WithJitter(false), WithName("cache"), shared)
+	db.Next()
+	cache.Next()
+	db.Next()
+	cache.Clone().Next()
+	want := []event{
+		{"db", 1, 100 * time.Millisecond},
+		{"cache", 1, 10 * time.Millisecond},
+		{"db", 2, 200 * time.Millisecond},
+		{"cache", 1, 10 * time.Millisecond},
+	}
+	if !reflect.DeepEqual(events, want) {
+		t.Errorf("named observer events = %v, want %v", events, want)
+	}
+
+	// both observers run when set together
+	calls := 0
+	both := New(100*time.Millisecond, 2.0, time.Second,
+		WithObserver(func(int, time.Duration) { calls++ }),
+		WithNamedObserver(func(string, int, time.Duration) { calls++ }))
+	both.Next()
+	if calls != 2 {
+		t.Errorf("observer calls = %d, want 2", calls)
+	}
+	if got := b.Clone().Name(); got != "cache" {
+		t.Errorf("Clone().Name() = %q, want %q", got, "cache")
+	}
+	if got := New(100*time.Millisecond, 2.0, time.Second).Name(); got != "" {
+		t.Errorf("Name() without WithName = %q, want empty", got)
+	}
+}
+
+func TestWithOnMax(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []Option
+	}{
+		{"lock-free path", []Option{WithJitter(false)}},
+		{"mutex path", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fired := 0
+			var b *Backoff
+			b = New(100*time.Millisecond, 2.0, 400*time.Millisecond, append(tt.opts,
+				WithOnMax(func() {
+					// runs outside the lock, so reading state must not deadlock
+					if !b.AtMax() {
+						t.Errorf("AtMax() inside callback = false, want true")
+					}
+					fired++
+				}))...)
+
+			// 100ms, 200ms, then 400ms saturates on the third call
+			for i, want := range []int{0, 0, 1, 1, 1, 1} {
+				b.Next()
+				if fired != want {
+					t.Fatalf("after Next() call %d fired %d times, want %d", i+1, fired, want)
+				}
+			}
+
+			// a new episode after Reset fires again, once
+			b.Reset()
+			b.NextN(6)
+			if fired != 2 {
+				t.Errorf("fired %d times after Reset() and re-saturation, want 2", fired)
+			}
+		})
+	}
+}
+
+func TestBackoff_ResetDelayOnly(t *testing.T) {
+	const ms = time.Millisecond
+
+	b := New(100*ms, 2.0, 1*time.Second, WithJitter(false), WithMaxAttempts(7))
+
+	var got []time.Duration
+	for range 3 {
+		got = append(got, b.NextN(2)...)
+		b.ResetDelayOnly()
+	}
+	if want := []time.Duration{100 * ms, 200 * ms, 100 * ms, 200 * ms, 100 * ms, 200 * ms}; !reflect.DeepEqual(got, want) {
+		t.Errorf("delays = %v, want %v", got, want)
+	}
+	if got := b.Attempt(); got != 6 {
+		t.Errorf("Attempt() = %d, want 6", got)
+	}
+	if got := b.Current(); got != 100*ms {
+		t.Errorf("Current() after ResetDelayOnly() = %v, want %v", got, 100*ms)
+	}
+
+	// the attempt limit still counts the whole lifetime
+	if _, ok := b.NextOK(); !ok {
+		t.Errorf("seventh NextOK() = false, want true")
+	}
+	if _, ok := b.NextOK(); ok {
+		t.Errorf("eighth NextOK() = true, want false")
+	}
+
+	b.Reset()
+	if got := b.Attempt(); got != 0 {
+		t.Errorf("Attempt() after Reset() = %d, want 0", got)
+	}
+}
+
+func TestBackoff_ResetJittered(t *testing.T) {
+	const (
+		initial = 100 * time.Millisecond
+		spread  = 50 * time.Millisecond
+	)
+
+	tests := []struct {
+		name string
+		opts []Option
+	}{
+		{"no jitter", []Option{WithJitter(false)}},
+		{"full jitter ignored on first call", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			seen := map[time.Duration]bool{}
+			for seed := range uint64(50) {
+				b := New(initial, 2.0, 10*time.Second, append(tt.opts, WithRand(rand.New(rand.NewPCG(seed, 0))))...)
+				b.NextN(3)
+
+				b.ResetJittered(spread)
+				if got := b.Attempt(); got != 0 {
+					t.Fatalf("Attempt() after ResetJittered() = %d, want 0", got)
+				}
+				d := b.Next()
+				if d < initial || d > initial+spread {
+					t.Fatalf("seed %d: first Next() = %v, want in [%v, %v]", seed, d, initial, initial+spread)
+				}
+				seen[d] = true
+				// subsequent calls grow normally from initial
+				if got := b.Peek(); got != 2*initial {
+					t.Errorf("Peek() after first Next() = %v, want %v", got, 2*initial)
+				}
+				if len(tt.opts) > 0 {
+					if got := b.Next(); got != 2*initial {
+						t.Errorf("second Next() = %v, want %v", got, 2*initial)
+					}
+				}
+
+				// a plain Reset drops the pending spread
+				b.ResetJittered(spread)
+				b.Reset()
+				if len(tt.opts) > 0 {
+					if got := b.Next(); got != initial {
+						t.Errorf("Next() after Reset() = %v, want %v", got, initial)
+					}
+				}
+			}
+			if len(seen) < 10 {
+				t.Errorf("first Next() took %d distinct values, want a spread", len(seen))
+			}
+		})
+	}
+}
+
+func TestWithObserver(t *testing.T) {
+	type observation struct {
+		attempt int
+		delay   time.Duration
+	}
+
+	tests := []struct {
+		name string
+		opts []Option
+	}{
+		{"mutex path", []Option{WithMaxAttempts(10)}},
+		{"lock-free path", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []observation
+			var b *Backoff
+			b = New(100*time.Millisecond, 2.0, 1*time.Second, append(tt.opts, WithJitter(false),
+				WithObserver(func(attempt int, delay time.Duration) {
+					// runs outside the lock, so reading state must not deadlock
+					if n := b.Attempt(); n < attempt {
+						t.Errorf("Attempt() inside observer = %d, want at least %d", n, attempt)
+					}
+					got = append(got, observation{attempt, delay})
+				}))...)
+
+			b.Next()
+			b.NextOK()
+			b.NextN(2)
+			b.NextBefore(time.Now().Add(time.Hour))
+
+			want := []observation{
+				{1, 100 * time.Millisecond},
+				{2, 200 * time.Millisecond},
+				{3, 400 * time.Millisecond},
+				{4, 800 * time.Millisecond},
+				{5, 1 * time.Second},
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("observed %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestWithContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	b := New(1*time.Millisecond, 2.0, 10*time.Millisecond, WithContext(ctx))
+
+	iterations := 0
+	for b.Continue() {
+		if _, ok := b.NextOK(); !ok {
+			t.Fatalf("NextOK() = false while Continue() = true")
+		}
+		iterations++
+		if iterations == 3 {
+			cancel()
+		}
+	}
+	if iterations != 3 {
+		t.Errorf("loop ran %d times, want 3", iterations)
+	}
+	if d, ok := b.NextOK(); ok {
+		t.Errorf("NextOK() after cancel = %v, true, want false", d)
+	}
+	// Continue does not advance the state
+	if got := b.Attempt(); got != 3 {
+		t.Errorf("Attempt() = %d, want 3", got)
+	}
+
+	if !New(1*time.Millisecond, 2.0, 10*time.Millisecond).Continue() {
+		t.Errorf("Continue() without context or limits = false, want true")
+	}
+}
+
+func TestBackoff_Try(t *testing.T) {
+	tests := []struct {
+		name      string
+		opts      []Option
+		wantCalls int
+	}{
+		{"max attempts", []Option{WithMaxAttempts(3)}, 3},
+		// 100ms + 200ms + 400ms, 800ms would exceed the total
+		{"max total delay", []Option{WithMaxTotalDelay(1 * time.Second)}, 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := New(100*time.Millisecond, 2.0, 10*time.Second, append(tt.opts, WithJitter(false))...)
+
+			for i := 0; i < tt.wantCalls; i++ {
+				if _, err := b.Try(); err != nil {
+					t.Fatalf("Try() call %d error = %v, want nil", i+1, err)
+				}
+			}
+			d, err := b.Try()
+			if !errors.Is(err, ErrExhausted) || d != 0 {
+				t.Errorf("Try() after limit = (%v, %v), want (0, %v)", d, err, ErrExhausted)
+			}
+
+			b.Reset()
+			if _, err := b.Try(); err != nil {
+				t.Errorf("Try() after Reset() error = %v, want nil", err)
+			}
+		})
+	}
+}
+
+func TestBackoff_MaxTotalDelay(t *testing.T) {
+	b := New(100*time.Millisecond, 2.0, 10*time.Second,
+		WithJitter(false), WithMaxTotalDelay(700*time.Millisecond))
+
+	want := []time.Duration{100 * time.Millisecond, 200 * time.Millisecond, 400 * time.Millisecond}
+	for i, w := range want {
+		d, ok := b.NextOK()
+		if !ok || d != w {
+			t.Fatalf("NextOK() call %d = %v, %v, want %v, true", i+1, d, ok, w)
+		}
+	}
+	// 800ms would bring the total to 1.5s
+	if d, ok := b.NextOK(); ok {
+		t.Errorf("NextOK() over the total = %v, true, want false", d)
+	}
+	if got := b.Attempt(); got != 3 {
+		t.Errorf("Attempt() after rejected NextOK() = %d, want 3", got)
+	}
+
+	// Reset clears the accumulated total
+	b.Reset()
+	if d, ok := b.NextOK(); !ok || d != 100*time.Millisecond {
+		t.Errorf("NextOK() after Reset() = %v, %v, want 100ms, true", d, ok)
+	}
+
+	// with jitter the sum of the accepted delays never exceeds the limit
+	max := 2 * time.Second
+	jb := New(100*time.Millisecond, 2.0, 10*time.Second,
+		WithRand(rand.New(rand.NewPCG(42, 0))), WithMaxTotalDelay(max))
+	var total time.Duration
+	for d := range jb.Seq() {
+		total += d
+	}
+	if total > max {
+		t.Errorf("sum of Seq() = %v, want at most %v", total, max)
+	}
+}
+
+func TestBackoff_Seq(t *testing.T) {
+	t.Run("stops at max attempts", func(t *testing.T) {
+		b := New(10*time.Millisecond, 2.0, 1*time.Second, WithJitter(false), WithMaxAttempts(4))
+
+		var got []time.Duration
+		for d := range b.Seq() {
+			got = append(got, d)
+		}
+		want := []time.Duration{
+			10 * time.Millisecond,
+			20 * time.Millisecond,
+			40 * time.Millisecond,
+			80 * time.Millisecond,
+		}
+		if len(got) != len(want) {
+			t.Fatalf("Seq() yielded %d values, want %d", len(got), len(want))
+		}
+		for i := range want {
+			if got[i] != want[i] {
+				t.Errorf("Seq() value %d = %v, want %v", i, got[i], want[i])
+			}
+		}
+	})
+
+	t.Run("break leaves consistent state", func(t *testing.T) {
+		b := New(10*time.Millisecond, 2.0, 1*time.Second, WithJitter(false))
+
+		yielded := 0
+		for range b.Seq() {
+			yielded++
+			if yielded == 3 {
+				break
+			}
+		}
+		if got := b.Attempt(); got != yielded {
+			t.Errorf("Attempt() after break = %d, want %d", got, yielded)
+		}
+		if got := b.Next(); got != 80*time.Millisecond {
+			t.Errorf("Next() after break = %v, want %v", got, 80*time.Millisecond)
+		}
+	})
+}
+
+func TestBackoff_Duration(t *testing.T) {
+	b := New(100*time.Millisecond, 2.0, 1*time.Second, WithJitter(false))
+
+	tests := []struct {
+		attempt int
+		want    time.Duration
+	}{
+		{-1, 100 * time.Millisecond},
+		{0, 100 * time.Millisecond},
+		{1, 200 * time.Millisecond},
+		{3, 800 * time.Millisecond},
+		{4, 1 * time.Second},
+		{1000, 1 * time.Second},
+	}
+
+	for _, tt := range tests {
+		if got := b.Duration(tt.attempt); got != tt.want {
+			t.Errorf("Duration(%d) = %v, want %v", tt.attempt, got, tt.want)
+		}
+	}
+
+	// Must agree with the stateful sequence
+	for i, want := range b.Schedule(6) {
+		if got := b.Duration(i); got != want {
+			t.Errorf("Duration(%d) = %v, Schedule()[%d] = %v", i, got, i, want)
+		}
+	}
+
+	if got := b.Attempt(); got != 0 {
+		t.Errorf("Attempt() after Duration() = %d, want 0", got)
+	}
+
+	// Jitter is applied within the window for the attempt
+	j := New(100*time.Millisecond, 2.0, 1*time.Second)
+	for i := 0; i < 100; i++ {
+		if got := j.Duration(2); got < 0 || got > 400*time.Millisecond {
+			t.Fatalf("Duration(2) with jitter = %v, want range [0, %v]", got, 400*time.Millisecond)
+		}
+	}
+}
+
+func TestBackoff_Channel(t *testing.T) {
+	t.Run("delivers delays until canceled", func(t *testing.T) {
+		b := New(1*time.Millisecond, 2.0, 4*time.Millisecond, WithJitter(false))
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		ch := b.Channel(ctx)
+		want := []time.Duration{1 * time.Millisecond, 2 * time.Millisecond, 4 * time.Millisecond}
+		for i, w := range want {
+			start := time.Now()
+			got := <-ch
+			if got != w {
+				t.Errorf("receive %d = %v, want %v", i+1, got, w)
+			}
+			if elapsed := time.Since(start); elapsed < w {
+				t.Errorf("receive %d arrived after %v, before waiting %v", i+1, elapsed, w)
+			}
+		}
+
+		cancel()
+		for range ch {
+			// drain until the goroutine closes the channel
+		}
+	})
+
+	t.Run("closes when attempts are exhausted", func(t *testing.T) {
+		b := New(1*time.Millisecond, 1.0, 1*time.Millisecond, WithJitter(false), WithMaxAttempts(2))
+
+		n := 0
+		for range b.Channel(context.Background()) {
+			n++
+		}
+		if n != 2 {
+			t.Errorf("Channel() delivered %d values, want 2", n)
+		}
+	})
+}
+
+func TestBackoff_NextBefore(t *testing.T) {
+	clk := newFakeClock()
+
+	tests := []struct {
+		name        string
+		remaining   time.Duration
+		want        time.Duration
+		wantOK      bool
+		wantAttempt int
+	}{
+		{"plenty of time", 10 * time.Second, 1 * time.Second, true, 3},
+		{"deadline mid-interval", 800 * time.Millisecond, 800 * time.Millisecond, true, 3},
+		{"deadline passed", -1 * time.Second, 0, false, 2},
+		{"deadline now", 0, 0, false, 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := New(250*time.Millisecond, 2.0, 5*time.Second, WithJitter(false), WithClock(clk))
+			b.Next()
+			b.Next()
+
+			got, ok := b.NextBefore(clk.Now().Add(tt.remaining))
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("NextBefore() = (%v, %v), want (%v, %v)", got, ok, tt.want, tt.wantOK)
+			}
+			if got := b.Attempt(); got != tt.wantAttempt {
+				t.Errorf("Attempt() after NextBefore() = %d, want %d", got, tt.wantAttempt)
+			}
+		})
+	}
+}
+
+func TestBackoff_NextContext(t *testing.T) {
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+	expired, cancel := context.WithDeadline(context.Background(), time.Now().Add(-time.Second))
+	defer cancel()
+
+	tests := []struct {
+		name        string
+		ctx         context.Context
+		want        time.Duration
+		wantErr     error
+		wantAttempt int
+	}{
+		{"active context", context.Background(), 400 * time.Millisecond, nil, 3},
+		{"canceled context", canceled, 0, context.Canceled, 2},
+		{"expired deadline", expired, 0, context.DeadlineExceeded, 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := New(100*time.Millisecond, 2.0, 5*time.Second, WithJitter(false))
+			b.Next()
+			b.Next()
+
+			got, err := b.NextContext(tt.ctx)
+			if got != tt.want || !errors.Is(err, tt.wantErr) {
+				t.Errorf("NextContext() = (%v, %v), want (%v, %v)", got, err, tt.want, tt.wantErr)
+			}
+			if got := b.Attempt(); got != tt.wantAttempt {
+				t.Errorf("Attempt() after NextContext() = %d, want %d", got, tt.wantAttempt)
+			}
+		})
+	}
+}
+
+func TestBackoff_TotalDelay(t *testing.T) {
+	tests := []struct {
+		name     string
+		attempts int
+		want     time.Duration
+	}{
+		{"zero attempts", 0, 0},
+		{"below cap", 3, 700 * time.Millisecond},
+		{"honors cap", 6, 100*time.Millisecond + 200*time.Millisecond + 400*time.Millisecond + 3*500*time.Millisecond},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := New(100*time.Millisecond, 2.0, 500*time.Millisecond)
+			if got := b.TotalDelay(tt.attempts); got != tt.want {
+				t.Errorf("TotalDelay(%d) = %v, want %v", tt.attempts, got, tt.want)
+			}
+			if got := b.Attempt(); got != 0 {
+				t.Errorf("Attempt() after TotalDelay() = %d, want 0", got)
+			}
+		})
+	}
+
+	b := New(1*time.Second, 10.0, time.Duration(math.MaxInt64))
+	if got := b.TotalDelay(100); got != math.MaxInt64 {
+		t.Errorf("TotalDelay() on overflow = %v, want saturation", got)
+	}
+}
+
+func TestBackoff_Reset(t *testing.T) {
+	b := New(100*time.Millisecond, 2.0, 1*time.Second, WithJitter(false))
+
+	// Call Next a few times to advance state
+	first := b.Next()
+	second := b.Next()
+
+	// Verify progression
+	if first != 100*time.Millisecond {
+		t.Errorf("First Next() = %v, want %v", first, 100*time.Millisecond)
+	}
+	if second != 200*time.Millisecond {
+		t.Errorf("Second Next() = %v, want %v", second, 200*time.Millisecond)
+	}
+
+	// Reset and verify it starts over
+	b.Reset()
+	afterReset := b.Next()
+	if afterReset != 100*time.Millisecond {
+		t.Errorf("Next() after Reset() = %v, want %v", afterReset, 100*time.Millisecond)
+	}
+}
+
+func TestWithGrowthFunc(t *testing.T) {
+	// Quadratic growth: initial * (attempt+1)^2
+	quadratic := func(attempt int, prev, initial, max time.Duration) time.Duration {
+		n := time.Duration(attempt + 1)
+		return initial * n * n
+	}
+
+	b := New(10*time.Millisecond, 2.0, 200*time.Millisecond, WithJitter(false), WithGrowthFunc(quadratic))
+	want := []time.Duration{
+		10 * time.Millisecond,
+		40 * time.Millisecond,
+		90 * time.Millisecond,
+		160 * time.Millisecond,
+		200 * time.Millisecond, // 250ms capped at max
+	}
+
+	for i, w := range want {
+		if got := b.Duration(i); got != w {
+			t.Errorf("Duration(%d) = %v, want %v", i, got, w)
+		}
+		if got := b.Next(); got != w {
+			t.Errorf("Next() call %d = %v, want %v", i+1, got, w)
+		}
+	}
+
+	// Jitter still wraps the function's output
+	j := New(10*time.Millisecond, 2.0, 200*time.Millisecond, WithGrowthFunc(quadratic))
+	for i := 0; i < 10; i++ {
+		base := j.Peek()
+		if got := j.Next(); got < 0 || got > base {
+			t.Errorf("Next() with jitter = %v, want range [0, %v]", got, base)
+		}
+	}
+}
+
+func TestWithGrowthFunc_Leading(t *testing.T) {
+	linear := func(attempt int, prev, initial, max time.Duration) time.Duration {
+		return time.Duration(attempt) * time.Second
+	}
+
+	tests := []struct {
+		name string
+		opt  Option
+	}{
+		{"WithFirstDelay", WithFirstDelay(time.Millisecond)},
+		{"WithImmediateFirst", WithImmediateFirst()},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := New(time.Second, 2.0, time.Minute, WithJitter(false), WithGrowthFunc(linear), tt.opt)
+			s := b.Schedule(6)
+			for i, want := range s {
+				if got := b.Duration(i); got != want {
+					t.Errorf("Duration(%d) = %v, want Schedule()[%d] = %v", i, got, i, want)
+				}
+				if got := b.Next(); got != want {
+					t.Errorf("Next() call %d = %v, want %v", i+1, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestBackoff_Success(t *testing.T) {
+	tests := []struct {
+		name     string
+		opts     []Option
+		wantPeek []time.Duration
+	}{
+		{
+			name: "default halves",
+			wantPeek: []time.Duration{
+				1600 * time.Millisecond, // current 1.6s halved to 0.8s, then doubled
+				800 * time.Millisecond,
+				400 * time.Millisecond,
+				200 * time.Millisecond,
+				200 * time.Millisecond, // floor at initial
+			},
+		},
+		{
+			name: "custom factor",
+			opts: []Option{WithDecreaseFactor(0.25)},
+			wantPeek: []time.Duration{
+				800 * time.Millisecond,
+				200 * time.Millisecond,
+				200 * time.Millisecond,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := append([]Option{WithJitter(false)}, tt.opts...)
+			b := New(100*time.Millisecond, 2.0, 10*time.Second, opts...)
+			for i := 0; i < 5; i++ {
+				b.Next() // current = 1.6s
+			}
+
+			for i, want := range tt.wantPeek {
+				b.Success()
+				if got := b.Peek(); got != want {
+					t.Errorf("Peek() after %d Success() = %v, want %v", i+1, got, want)
+				}
+			}
+		})
+	}
+
+	// Success before the first Next is a no-op
+	b := New(100*time.Millisecond, 2.0, 10*time.Second, WithJitter(false))
+	b.Success()
+	if got := b.Next(); got != 100*time.Millisecond {
+		t.Errorf("Next() after early Success() = %v, want %v", got, 100*time.Millisecond)
+	}
+}
+
+func TestBackoff_Observe(t *testing.T) {
+	b := New(100*time.Millisecond, 2.0, 1*time.Second, WithJitter(false))
+	b.Next()
+	b.Next()
+
+	b.Observe(errors.New("failure"))
+	if got := b.Next(); got != 400*time.Millisecond {
+		t.Errorf("Next() after error observation = %v, want %v", got, 400*time.Millisecond)
+	}
+
+	b.Observe(nil)
+	if got := b.Next(); got != 100*time.Millisecond {
+		t.Errorf("Next() after nil observation = %v, want %v", got, 100*time.Millisecond)
+	}
+}
+
+func TestBackoff_ResetTo(t *testing.T) {
+	tests := []struct {
+		name        string
+		attempt     int
+		wantAttempt int
+		wantNext    []time.Duration
+	}{
+		{
+			name:        "resume at attempt 3",
+			attempt:     3,
+			wantAttempt: 4,
+			wantNext:    []time.Duration{1600 * time.Millisecond, 2 * time.Second},
+		},
+		{
+			name:        "attempt 0",
+			attempt:     0,
+			wantAttempt: 1,
+			wantNext:    []time.Duration{200 * time.Millisecond, 400 * time.Millisecond},
+		},
+		{
+			name:        "negative treated as 0",
+			attempt:     -5,
+			wantAttempt: 1,
+			wantNext:    []time.Duration{200 * time.Millisecond, 400 * time.Millisecond},
+		},
+		{
+			name:        "beyond max",
+			attempt:     50,
+			wantAttempt: 51,
+			wantNext:    []time.Duration{2 * time.Second, 2 * time.Second},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := New(100*time.Millisecond, 2.0, 2*time.Second, WithJitter(false))
+			b.Next()
+
+			b.ResetTo(tt.attempt)
+			if got := b.Attempt(); got != tt.wantAttempt {
+				t.Errorf("Attempt() after ResetTo(%d) = %d, want %d", tt.attempt, got, tt.wantAttempt)
+			}
+			for i, want := range tt.wantNext {
+				if got := b.Next(); got != want {
+					t.Errorf("Next() call %d after ResetTo(%d) = %v, want %v", i+1, tt.attempt, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestBackoff_Attempt(t *testing.T) {
+	b := New(100*time.Millisecond, 2.0, 1*time.Second, WithJitter(false))
+
+	if got := b.Attempt(); got != 0 {
+		t.Errorf("Attempt() before Next() = %d, want 0", got)
+	}
+
+	for i := 1; i <= 3; i++ {
+		b.Next()
+		if got := b.Attempt(); got != i {
+			t.Errorf("Attempt() after %d Next() calls = %d, want %d", i, got, i)
+		}
+	}
+
+	b.Reset()
+	if got := b.Attempt(); got != 0 {
+		t.Errorf("Attempt() after Reset() = %d, want 0", got)
+	}
+}
+
+func TestBackoff_Current(t *testing.T) {
+	b := New(100*time.Millisecond, 2.0, 300*time.Millisecond)
+
+	if got := b.Current(); got != 100*time.Millisecond {
+		t.Errorf("Current() before Next() = %v, want %v", got, 100*time.Millisecond)
+	}
+
+	want := []time.Duration{100 * time.Millisecond, 200 * time.Millisecond, 300 * time.Millisecond}
+	for i, w := range want {
+		b.Next()
+		if got := b.Current(); got != w {
+			t.Errorf("Current() after call %d = %v, want %v", i+1, got, w)
+		}
+		if got := b.Current(); got != w {
+			t.Errorf("Current() must not advance state, got %v, want %v", got, w)
+		}
+	}
+}
+
+func TestBackoff_AtMax(t *testing.T) {
+	for _, strategy := range []JitterStrategy{NoJitter, FullJitter, Decorrelated} {
+		t.Run(strategy.String(), func(t *testing.T) {
+			b := New(100*time.Millisecond, 2.0, 400*time.Millisecond, WithJitterStrategy(strategy))
+
+			if b.AtMax() {
+				t.Errorf("AtMax() before Next() = true, want false")
+			}
+			// 100ms, 200ms, 400ms, 400ms
+			want := []bool{false, false, true, true}
+			for i, w := range want {
+				b.Next()
+				if got := b.AtMax(); got != w {
+					t.Errorf("AtMax() after call %d = %v, want %v", i+1, got, w)
+				}
+			}
+
+			b.Reset()
+			if b.AtMax() {
+				t.Errorf("AtMax() after Reset() = true, want false")
+			}
+		})
+	}
+}
+
+func TestBackoff_Peek(t *testing.T) {
+	tests := []struct {
+		name   string
+		jitter bool
+	}{
+		{"without jitter", false},
+		{"with jitter returns upper bound", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := New(100*time.Millisecond, 2.0, 300*time.Millisecond, WithJitter(tt.jitter))
+			expected := []time.Duration{
+				100 * time.Millisecond,
+				200 * time.Millisecond,
+				300 * time.Millisecond, // capped at max
+			}
+			for i, want := range expected {
+				// Peek must be idempotent
+				if got := b.Peek(); got != want {
+					t.Errorf("Peek() before call %d = %v, want %v", i+1, got, want)
+				}
+				if got := b.Peek(); got != want {
+					t.Errorf("second Peek() before call %d = %v, want %v", i+1, got, want)
+				}
+				got := b.Next()
+				if !tt.jitter && got != want {
+					t.Errorf("Next() call %d = %v, want %v", i+1, got, want)
+				}
+				if got > want {
+					t.Errorf("Next() call %d = %v, exceeds Peek() %v", i+1, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestBackoff_NextOK(t *testing.T) {
+	tests := []struct {
+		name        string
+		maxAttempts int
+		calls       int
+		wantOK      int
+	}{
+		{"unlimited", 0, 10, 10},
+		{"limited to three", 3, 5, 3},
+		{"limited to one", 1, 3, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := New(10*time.Millisecond, 2.0, 1*time.Second,
+				WithJitter(false), WithMaxAttempts(tt.maxAttempts))
+
+			for round := 0; round < 2; round++ {
+				ok := 0
+				for i := 0; i < tt.calls; i++ {
+					if _, more := b.NextOK(); more {
+						ok++
+					}
+				}
+				if ok != tt.wantOK {
+					t.Errorf("round %d: NextOK() succeeded %d times, want %d", round+1, ok, tt.wantOK)
+				}
+				// Reset must restore the attempt budget
+				b.Reset()
+			}
+		})
+	}
+}
+
+func TestBackoff_Clone(t *testing.T) {
+	b := New(100*time.Millisecond, 2.0, 1*time.Second,
+		WithJitterStrategy(EqualJitter), WithMaxAttempts(5),
+		WithRand(rand.New(rand.NewPCG(42, 0))))
+	b.Next()
+	b.Next()
+
+	c := b.Clone()
+	if !reflect.DeepEqual(c.config, b.config) {
+		t.Errorf("Clone() config = %+v, want %+v", c.config, b.config)
+	}
+	if c.Attempt() != 0 || c.Peek() != 100*time.Millisecond {
+		t.Errorf("Clone() should start fresh, got attempt=%d peek=%v", c.Attempt(), c.Peek())
+	}
+	if c.rnd == nil || c.rnd == b.rnd {
+		t.Errorf("Clone() should own a separate random source")
+	}
+
+	// Advancing the clone must not affect the original
+	c.Next()
+	c.Next()
+	c.Next()
+	if got := b.Attempt(); got != 2 {
+		t.Errorf("original Attempt() after advancing clone = %d, want 2", got)
+	}
+	if got := b.Peek(); got != 400*time.Millisecond {
+		t.Errorf("original Peek() after advancing clone = %v, want %v", got, 400*time.Millisecond)
+	}
+}
+
+func TestBackoff_String(t *testing.T) {
+	tests := []struct {
+		name  string
+		b     *Backoff
+		calls int
+		want  string
+	}{
+		{
+			name:  "full jitter",
+			b:     New(100*time.Millisecond, 2.0, 5*time.Second),
+			calls: 3,
+			want:  "Backoff(initial=100ms, factor=2.00, max=5s, jitter=full, attempt=3)",
+		},
+		{
+			name: "no jitter",
+			b:    New(1*time.Second, 1.5, 30*time.Second, WithJitter(false)),
+			want: "Backoff(initial=1s, factor=1.50, max=30s, jitter=none, attempt=0)",
+		},
+		{
+			name: "equal jitter",
+			b:    New(1*time.Second, 2.0, 1*time.Minute, WithJitterStrategy(EqualJitter)),
+			want: "Backoff(initial=1s, factor=2.00, max=1m0s, jitter=equal, attempt=0)",
+		},
+		{
+			name:  "named",
+			b:     New(1*time.Second, 2.0, 1*time.Minute, WithJitter(false), WithName("db")),
+			calls: 1,
+			want:  `Backoff(name="db", initial=1s, factor=2.00, max=1m0s, jitter=none, attempt=1)`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for i := 0; i < tt.calls; i++ {
+				tt.b.Next()
+			}
+			if got := tt.b.String(); got != tt.want {
+				t.Errorf("String() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	if got := JitterStrategy(99).String(); got != "JitterStrategy(99)" {
+		t.Errorf("JitterStrategy(99).String() = %q", got)
+	}
+}
+
+func TestBackoff_Concurrency(t *testing.T) {
+	b := New(10*time.Millisecond, 1.5, 100*time.Millisecond, WithJitter(false))
+
+	const numGoroutines = 100
+	const callsPerGoroutine = 10
+
+	var wg sync.WaitGroup
+	results := make([][]time.Duration, numGoroutines)
+
+	// Launch concurrent goroutines
+	for i := 0; i < numGoroutines; i++ {
+		wg.Add(1)
+		go func(index int) {
+			defer wg.Done()
+			for j := 0; j < callsPerGoroutine; j++ {
+				duration := b.Next()
+				results[index] = append(results[index], duration)
+			}
+		}(i)
+	}
+
+	wg.Wait()
+
+	// Verify all results are valid (non-zero and within reasonable bounds)
+	for i, goroutineResults := range results {
+		for j, duration := range goroutineResults {
+			if duration <= 0 {
+				t.Errorf("Goroutine %d, call %d: got non-positive duration %v", i, j, duration)
+			}
+			if duration > 100*time.Millisecond {
+				t.Errorf("Goroutine %d, call %d: got duration %v exceeding max %v", i, j, duration, 100*time.Millisecond)
+			}
+		}
+	}
+}
+
+func TestBackoff_EdgeCases(t *testing.T) {
 	tests := []struct {
 		name    string
 		initial time.Duration
@@ -313,6 +3037,115 @@ func TestBackoff_EdgeCases(t *testing.T) {
 	}
 }
 
+func TestBackoff_Overflow(t *testing.T) {
+	const max = time.Duration(math.MaxInt64)
+	b := New(1*time.Second, 10.0, max, WithJitter(false))
+
+	prev := time.Duration(0)
+	for i := 0; i < 100; i++ {
+		d := b.Next()
+		if d < prev {
+			t.Fatalf("Next() call %d = %v, smaller than previous %v", i+1, d, prev)
+		}
+		prev = d
+	}
+	if prev != max {
+		t.Errorf("Next() after overflow = %v, want %v", prev, max)
+	}
+}
+
+func TestBackoff_Unbounded(t *testing.T) {
+	tests := []struct {
+		name    string
+		max     time.Duration
+		wantCap time.Duration
+	}{
+		{"bounded", 10 * time.Second, 10 * time.Second},
+		{"zero max is unbounded", 0, math.MaxInt64},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := New(1*time.Second, 2.0, tt.max, WithJitter(false))
+
+			prev := time.Duration(0)
+			for i := 0; i < 100; i++ {
+				d := b.Next()
+				if d < prev || d > tt.wantCap {
+					t.Fatalf("Next() call %d = %v, previous %v, cap %v", i+1, d, prev, tt.wantCap)
+				}
+				prev = d
+			}
+			if prev != tt.wantCap {
+				t.Errorf("Next() after 100 calls = %v, want %v", prev, tt.wantCap)
+			}
+			if got := b.Duration(10); got != min(1024*time.Second, tt.wantCap) {
+				t.Errorf("Duration(10) = %v, want %v", got, min(1024*time.Second, tt.wantCap))
+			}
+		})
+	}
+
+	// jitter still draws from [0, current] without a cap
+	for _, s := range []JitterStrategy{FullJitter, EqualJitter, Decorrelated, ProportionalJitter} {
+		t.Run("jitter "+s.String(), func(t *testing.T) {
+			b := New(1*time.Second, 2.0, 0, WithJitterStrategy(s), WithRand(rand.New(rand.NewPCG(42, 0))))
+			if s == ProportionalJitter {
+				b = New(1*time.Second, 2.0, 0, WithJitterFactor(0.5), WithRand(rand.New(rand.NewPCG(42, 0))))
+			}
+			for i := 0; i < 100; i++ {
+				d := b.Next()
+				if d < 0 {
+					t.Fatalf("Next() call %d = %v, want non-negative", i+1, d)
+				}
+				if s == FullJitter || s == EqualJitter {
+					if cur := b.Current(); d > cur {
+						t.Fatalf("Next() call %d = %v, want at most Current() %v", i+1, d, cur)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestBackoff_NextConcurrentMonotonic(t *testing.T) {
+	const (
+		goroutines = 8
+		calls      = 1000
+		max        = 5 * time.Second
+	)
+	b := New(1*time.Millisecond, 1.5, max, WithJitter(false))
+
+	var wg sync.WaitGroup
+	errs := make(chan error, goroutines)
+	for range goroutines {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			prev := time.Duration(0)
+			for i := range calls {
+				d := b.Next()
+				if d < prev || d > max {
+					errs <- fmt.Errorf("Next() call %d = %v, previous %v, max %v", i+1, d, prev, max)
+					return
+				}
+				prev = d
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		t.Error(err)
+	}
+	if got := b.Attempt(); got != goroutines*calls {
+		t.Errorf("Attempt() = %d, want %d", got, goroutines*calls)
+	}
+	if got := b.Current(); got != max {
+		t.Errorf("Current() = %v, want %v", got, max)
+	}
+}
+
 func BenchmarkBackoff_Next(b *testing.B) {
 	backoff := New(100*time.Millisecond, 2.0, 10*time.Second, WithJitter(false))
 
@@ -331,12 +3164,340 @@ func BenchmarkBackoff_NextWithJitter(b *testing.B) {
 	}
 }
 
+func BenchmarkBackoff_NextWithCryptoJitter(b *testing.B) {
+	backoff := New(100*time.Millisecond, 2.0, 10*time.Second, WithCryptoJitter())
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = backoff.Next()
+	}
+}
+
+func BenchmarkBackoff_ConcurrentWithJitter(b *testing.B) {
+	benchmarks := []struct {
+		name string
+		opts []Option
+	}{
+		{"per-instance source", nil},
+		{"global source", []Option{WithRand(nil)}},
+	}
+
+	for _, bm := range benchmarks {
+		b.Run(bm.name, func(b *testing.B) {
+			b.RunParallel(func(pb *testing.PB) {
+				// One backoff per goroutine, so only the random source is shared
+				backoff := New(100*time.Millisecond, 2.0, 10*time.Second, bm.opts...)
+				for pb.Next() {
+					_ = backoff.Next()
+				}
+			})
+		})
+	}
+}
+
 func BenchmarkBackoff_Concurrent(b *testing.B) {
-	backoff := New(100*time.Millisecond, 2.0, 10*time.Second, WithJitter(false))
+	benchmarks := []struct {
+		name string
+		next func(*Backoff)
+	}{
+		// Next without jitter advances the state atomically
+		{"lock-free", func(b *Backoff) { _ = b.Next() }},
+		// NextOK always takes the mutex, as Next did before
+		{"mutex", func(b *Backoff) { _, _ = b.NextOK() }},
+	}
+
+	for _, bm := range benchmarks {
+		b.Run(bm.name, func(b *testing.B) {
+			backoff := New(100*time.Millisecond, 2.0, 10*time.Second, WithJitter(false))
+
+			b.ReportAllocs()
+			b.RunParallel(func(pb *testing.PB) {
+				for pb.Next() {
+					bm.next(backoff)
+				}
+			})
+		})
+	}
+}
+
+func TestBackoff_Wait(t *testing.T) {
+	tests := []struct {
+		name    string
+		ctx     func() (context.Context, context.CancelFunc)
+		initial time.Duration
+		wantErr error
+	}{
+		{
+			name: "clean sleep",
+			ctx: func() (context.Context, context.CancelFunc) {
+				return context.WithCancel(context.Background())
+			},
+			initial: 1 * time.Millisecond,
+			wantErr: nil,
+		},
+		{
+			name: "context already canceled",
+			ctx: func() (context.Context, context.CancelFunc) {
+				ctx, cancel := context.WithCancel(context.Background())
+				cancel()
+				return ctx, cancel
+			},
+			initial: 1 * time.Hour,
+			wantErr: context.Canceled,
+		},
+		{
+			name: "deadline before interval elapses",
+			ctx: func() (context.Context, context.CancelFunc) {
+				return context.WithTimeout(context.Background(), 10*time.Millisecond)
+			},
+			initial: 1 * time.Hour,
+			wantErr: context.DeadlineExceeded,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := tt.ctx()
+			defer cancel()
+
+			b := New(tt.initial, 2.0, 4*tt.initial, WithJitter(false))
+			err := b.Wait(ctx)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Wait() error = %v, want %v", err, tt.wantErr)
+			}
+
+			// State must have advanced exactly once
+			if got := b.Next(); got != 2*tt.initial {
+				t.Errorf("Next() after Wait() = %v, want %v", got, 2*tt.initial)
+			}
+		})
+	}
+}
+
+func TestBackoff_Reconfigure(t *testing.T) {
+	const ms = time.Millisecond
+
+	tests := []struct {
+		name    string
+		opts    []Option
+		factor  float64
+		max     time.Duration
+		wantErr bool
+		want    []time.Duration // Next() after 100ms, 200ms
+	}{
+		{"faster growth", nil, 3.0, 10 * time.Second, false, []time.Duration{600 * ms, 1800 * ms, 5400 * ms, 10 * time.Second}},
+		{"lower max", nil, 2.0, 500 * ms, false, []time.Duration{400 * ms, 500 * ms, 500 * ms}},
+		{"unbounded max", nil, 2.0, 0, false, []time.Duration{400 * ms, 800 * ms, 1600 * ms}},
+		{"factor below 1", nil, 0.5, 1 * time.Second, true, []time.Duration{400 * ms, 800 * ms, 1000 * ms}},
+		{"NaN factor", nil, math.NaN(), 1 * time.Second, true, []time.Duration{400 * ms, 800 * ms, 1000 * ms}},
+		{"max below initial", nil, 2.0, 50 * ms, true, []time.Duration{400 * ms, 800 * ms, 1000 * ms}},
+		{"negative max", nil, 2.0, -1, true, []time.Duration{400 * ms, 800 * ms, 1000 * ms}},
+		{"decay with floor", []Option{WithFloor(50 * ms)}, 0.5, 1 * time.Second, false, []time.Duration{100 * ms, 50 * ms, 50 * ms}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// no jitter takes the lock-free path in Next
+			b := New(100*ms, 2.0, 1*time.Second, append(tt.opts, WithJitter(false))...)
+			b.NextN(2)
+
+			err := b.Reconfigure(tt.factor, tt.max)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Reconfigure() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			// the progression position is kept
+			if got := b.Current(); got != 200*ms {
+				t.Errorf("Current() after Reconfigure() = %v, want %v", got, 200*ms)
+			}
+			var got []time.Duration
+			for range tt.want {
+				got = append(got, b.Next())
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Next() after Reconfigure() = %v, want %v", got, tt.want)
+			}
+			if !tt.wantErr {
+				c := b.Config()
+				if c.Factor != tt.factor || c.Max != tt.max {
+					t.Errorf("Config() = (%v, %v), want (%v, %v)", c.Factor, c.Max, tt.factor, tt.max)
+				}
+				if c := b.Clone().Config(); c.Factor != tt.factor || c.Max != tt.max {
+					t.Errorf("Clone().Config() = (%v, %v), want (%v, %v)", c.Factor, c.Max, tt.factor, tt.max)
+				}
+			}
+		})
+	}
+}
+
+func TestBackoff_ReconfigureConcurrent(t *testing.T) {
+	b := New(1*time.Millisecond, 2.0, 1*time.Second, WithJitter(false))
 
-	b.RunParallel(func(pb *testing.PB) {
-		for pb.Next() {
-			_ = backoff.Next()
+	var wg sync.WaitGroup
+	for range 4 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range 1000 {
+				if d := b.Next(); d < 0 || d > 2*time.Second {
+					t.Errorf("Next() = %v, want within [0, 2s]", d)
+					return
+				}
+			}
+		}()
+	}
+	for i := range 100 {
+		if err := b.Reconfigure(1.5+float64(i%2), time.Duration(1+i%2)*time.Second); err != nil {
+			t.Fatalf("Reconfigure() error = %v", err)
 		}
-	})
+	}
+	wg.Wait()
+}
+
+func TestBackoff_Scale(t *testing.T) {
+	const ms = time.Millisecond
+
+	tests := []struct {
+		name string
+		run  func(b *Backoff) []time.Duration
+		want []time.Duration
+	}{
+		{
+			name: "stretches current and max",
+			run: func(b *Backoff) []time.Duration {
+				out := []time.Duration{b.Next(), b.Next()}
+				b.Scale(3)
+				out = append(out, b.Current())
+				for range 3 {
+					out = append(out, b.Next())
+				}
+				return out
+			},
+			want: []time.Duration{100 * ms, 200 * ms, 600 * ms, 1200 * ms, 2400 * ms, 3000 * ms},
+		},
+		{
+			name: "factor is absolute",
+			run: func(b *Backoff) []time.Duration {
+				b.Next()
+				b.Scale(2)
+				b.Scale(3)
+				return []time.Duration{b.Current()}
+			},
+			want: []time.Duration{300 * ms},
+		},
+		{
+			name: "unscale restores baseline",
+			run: func(b *Backoff) []time.Duration {
+				b.Next()
+				b.Scale(4)
+				out := []time.Duration{b.Next()}
+				b.Unscale()
+				return append(out, b.Current(), b.Next(), b.Next(), b.Next())
+			},
+			want: []time.Duration{800 * ms, 200 * ms, 400 * ms, 800 * ms, 1000 * ms},
+		},
+		{
+			name: "shrinks",
+			run: func(b *Backoff) []time.Duration {
+				b.Scale(0.5)
+				return []time.Duration{b.Next(), b.Next()}
+			},
+			want: []time.Duration{50 * ms, 100 * ms},
+		},
+		{
+			name: "survives reset",
+			run: func(b *Backoff) []time.Duration {
+				b.Next()
+				b.Scale(3)
+				b.Reset()
+				return []time.Duration{b.Next()}
+			},
+			want: []time.Duration{300 * ms},
+		},
+		{
+			name: "invalid factors ignored",
+			run: func(b *Backoff) []time.Duration {
+				b.Next()
+				b.Scale(0)
+				b.Scale(-1)
+				b.Scale(math.NaN())
+				b.Scale(math.Inf(1))
+				return []time.Duration{b.Current(), b.Next()}
+			},
+			want: []time.Duration{100 * ms, 200 * ms},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := New(100*ms, 2.0, 1*time.Second, WithJitter(false))
+			if got := tt.run(b); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+			if got := b.Config().Initial; got != 100*ms {
+				t.Errorf("Config().Initial = %v, want %v", got, 100*ms)
+			}
+		})
+	}
+}
+
+func TestBackoff_AttemptConcurrent(t *testing.T) {
+	const (
+		writers = 4
+		calls   = 1000
+	)
+
+	tests := []struct {
+		name string
+		opts []Option
+	}{
+		{"lock-free path", []Option{WithJitter(false)}},
+		{"mutex path", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := New(1*time.Millisecond, 2.0, 1*time.Second, tt.opts...)
+
+			done := make(chan struct{})
+			var readers sync.WaitGroup
+			for range 4 {
+				readers.Add(1)
+				go func() {
+					defer readers.Done()
+					prev := 0
+					for {
+						n := b.Attempt()
+						if n < prev || n > writers*calls {
+							t.Errorf("Attempt() = %d after %d, want monotonic in [0, %d]", n, prev, writers*calls)
+							return
+						}
+						prev = n
+						select {
+						case <-done:
+							return
+						default:
+						}
+					}
+				}()
+			}
+
+			var wg sync.WaitGroup
+			for range writers {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					for range calls {
+						b.Next()
+					}
+				}()
+			}
+			wg.Wait()
+			close(done)
+			readers.Wait()
+
+			if got := b.Attempt(); got != writers*calls {
+				t.Errorf("Attempt() = %d, want %d", got, writers*calls)
+			}
+		})
+	}
 }
@@ -0,0 +1,89 @@
+package backoff
+
+import (
+	"sync"
+	"time"
+)
+
+// Budget é um orçamento de retries compartilhado entre vários Backoff, no
+// padrão "retry budget" de SRE: um balde de fichas que limita quantos retries
+// o serviço inteiro faz, evitando que as falhas de uma queda se multipliquem
+// em tempestades de retries. Cada retry autorizado consome uma ficha; as
+// fichas voltam com o tempo, uma a cada intervalo de reposição, até a
+// capacidade. É seguro para uso concorrente.
+type Budget struct {
+	mu       sync.Mutex
+	tokens   int           // fichas disponíveis
+	capacity int           // limite de fichas acumuladas
+	refill   time.Duration // intervalo de reposição de uma ficha (0 = nunca)
+	last     time.Time     // instante da última reposição
+	clock    Clock
+}
+
+// NewBudget cria um orçamento com tokens fichas, que também é a capacidade, e
+// repõe uma ficha a cada refill. refill igual a zero desliga a reposição: o
+// orçamento se esgota depois de tokens retries. Valores negativos de tokens
+// são tratados como 0.
+func NewBudget(tokens int, refill time.Duration) *Budget {
+	tokens = max(tokens, 0)
+	return &Budget{tokens: tokens, capacity: tokens, refill: refill, clock: realClock{}}
+}
+
+// Allow consome uma ficha e informa se havia alguma, ou seja, se o retry está
+// dentro do orçamento.
+func (b *Budget) Allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.fill()
+	if b.tokens <= 0 {
+		return false
+	}
+	b.tokens--
+	return true
+}
+
+// Available retorna quantas fichas restam, sem consumi-las.
+func (b *Budget) Available() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.fill()
+	return b.tokens
+}
+
+// fill repõe as fichas acumuladas desde a última reposição; exige b.mu
+// travado.
+func (b *Budget) fill() {
+	if b.refill <= 0 {
+		return
+	}
+	now := b.clock.Now()
+	if b.last.IsZero() {
+		b.last = now
+		return
+	}
+	n := now.Sub(b.last) / b.refill
+	if n <= 0 {
+		return
+	}
+	if int64(n) >= int64(b.capacity-b.tokens) {
+		b.tokens = b.capacity
+		b.last = now
+		return
+	}
+	b.tokens += int(n)
+	b.last = b.last.Add(n * b.refill)
+}
+
+// WithBudget faz NextOK consultar o orçamento compartilhado budget antes de
+// cada tentativa: quando os demais limites permitem continuar, uma ficha é
+// consumida, e sem fichas NextOK retorna false. Vários Backoff com o mesmo
+// Budget param juntos quando ele se esgota. Next, que não aplica limites, e
+// Continue, que não avança o estado, não consomem fichas. nil desliga a
+// opção.
+func WithBudget(budget *Budget) Option {
+	return func(b *Backoff) {
+		b.budget = budget
+	}
+}
@@ -0,0 +1,148 @@
+package backoff
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestBudget_Allow(t *testing.T) {
+	tests := []struct {
+		name   string
+		tokens int
+		want   int // calls allowed
+	}{
+		{"three tokens", 3, 3},
+		{"one token", 1, 1},
+		{"empty", 0, 0},
+		{"negative", -1, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewBudget(tt.tokens, 0)
+			allowed := 0
+			for range 10 {
+				if g.Allow() {
+					allowed++
+				}
+			}
+			if allowed != tt.want {
+				t.Errorf("Allow() succeeded %d times, want %d", allowed, tt.want)
+			}
+			if got := g.Available(); got != 0 {
+				t.Errorf("Available() = %d, want 0", got)
+			}
+		})
+	}
+}
+
+func TestBudget_Refill(t *testing.T) {
+	clock := newFakeClock()
+	g := NewBudget(3, time.Second)
+	g.clock = clock
+
+	for range 3 {
+		if !g.Allow() {
+			t.Fatal("Allow() = false, want true")
+		}
+	}
+	if g.Allow() {
+		t.Fatal("Allow() with no tokens = true, want false")
+	}
+
+	clock.Advance(1500 * time.Millisecond)
+	if got := g.Available(); got != 1 {
+		t.Errorf("Available() after 1.5s = %d, want 1", got)
+	}
+	// the half second left over counts toward the next token
+	clock.Advance(500 * time.Millisecond)
+	if got := g.Available(); got != 2 {
+		t.Errorf("Available() after 2s = %d, want 2", got)
+	}
+
+	// never above capacity
+	clock.Advance(time.Hour)
+	if got := g.Available(); got != 3 {
+		t.Errorf("Available() after an hour = %d, want 3", got)
+	}
+}
+
+func TestWithBudget(t *testing.T) {
+	g := NewBudget(5, 0)
+	backoffs := []*Backoff{
+		New(time.Millisecond, 2.0, time.Second, WithBudget(g)),
+		New(time.Millisecond, 2.0, time.Second, WithBudget(g)),
+		New(time.Millisecond, 2.0, time.Second, WithBudget(g), WithMaxTotalDelay(time.Hour)),
+	}
+
+	allowed := 0
+	for range 4 {
+		for _, b := range backoffs {
+			if _, ok := b.NextOK(); ok {
+				allowed++
+			}
+		}
+	}
+	if allowed != 5 {
+		t.Errorf("NextOK() succeeded %d times across backoffs, want 5", allowed)
+	}
+	for i, b := range backoffs {
+		if _, ok := b.NextOK(); ok {
+			t.Errorf("backoff %d: NextOK() after the budget drained = true, want false", i)
+		}
+		// Next does not consult the budget
+		if got := b.Next(); got < 0 {
+			t.Errorf("backoff %d: Next() = %v, want non-negative", i, got)
+		}
+	}
+
+	// attempts refused by other limits do not spend tokens
+	g = NewBudget(1, 0)
+	b := New(time.Millisecond, 2.0, time.Second, WithBudget(g), WithMaxAttempts(1))
+	b.NextOK()
+	b.NextOK()
+	if got := g.Available(); got != 0 {
+		t.Errorf("Available() = %d, want 0", got)
+	}
+	g = NewBudget(1, 0)
+	b = New(time.Millisecond, 2.0, time.Second, WithBudget(g), WithMaxAttempts(1))
+	b.Continue()
+	if got := g.Available(); got != 1 {
+		t.Errorf("Available() after Continue() = %d, want 1", got)
+	}
+}
+
+func TestWithBudget_Retry(t *testing.T) {
+	g := NewBudget(4, 0)
+	errFail := errors.New("fail")
+
+	var wg sync.WaitGroup
+	calls := make([]int, 3)
+	for i := range calls {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			b := New(time.Microsecond, 1.0, time.Microsecond, WithBudget(g))
+			err := Do(context.Background(), b, func() error {
+				calls[i]++
+				return errFail
+			}, nil)
+			if !errors.Is(err, errFail) {
+				t.Errorf("Do() = %v, want %v", err, errFail)
+			}
+		}()
+	}
+	wg.Wait()
+
+	// each caller makes one first call plus the retries the budget allows
+	total := 0
+	for _, n := range calls {
+		total += n
+	}
+	if want := len(calls) + 4; total != want {
+		t.Errorf("calls across Do() = %d, want %d", total, want)
+	}
+}
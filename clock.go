@@ -0,0 +1,50 @@
+package backoff
+
+import "time"
+
+// Clock abstrai a fonte de tempo usada pelo Backoff, permitindo controlar o
+// tempo em testes.
+type Clock interface {
+	Now() time.Time
+	NewTimer(d time.Duration) Timer
+}
+
+// Timer abstrai um *time.Timer.
+type Timer interface {
+	C() <-chan time.Time
+	Stop() bool
+	Reset(d time.Duration) bool
+}
+
+// WithClock define a fonte de tempo. O padrão é o relógio real.
+func WithClock(c Clock) Option {
+	return func(b *Backoff) {
+		b.clock = c
+	}
+}
+
+// realClock usa o pacote time.
+type realClock struct{}
+
+func (realClock) Now() time.Time { return time.Now() }
+
+func (realClock) NewTimer(d time.Duration) Timer { return realTimer{time.NewTimer(d)} }
+
+// realTimer adapta *time.Timer à interface Timer.
+type realTimer struct {
+	t *time.Timer
+}
+
+func (r realTimer) C() <-chan time.Time { return r.t.C }
+
+func (r realTimer) Stop() bool { return r.t.Stop() }
+
+func (r realTimer) Reset(d time.Duration) bool { return r.t.Reset(d) }
+
+// getClock retorna o relógio configurado ou o real.
+func (b *Backoff) getClock() Clock {
+	if b.clock == nil {
+		return realClock{}
+	}
+	return b.clock
+}
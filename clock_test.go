@@ -0,0 +1,380 @@
+package backoff
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeClock is a manually advanced Clock for tests.
+type fakeClock struct {
+	mu     sync.Mutex
+	now    time.Time
+	timers []*fakeTimer
+	added  chan struct{} // receives one value per NewTimer or Timer.Reset call
+}
+
+func newFakeClock() *fakeClock {
+	return &fakeClock{
+		now:   time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC),
+		added: make(chan struct{}, 100),
+	}
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) NewTimer(d time.Duration) Timer {
+	c.mu.Lock()
+	t := &fakeTimer{clock: c, ch: make(chan time.Time, 1)}
+	c.timers = append(c.timers, t)
+	t.schedule(d)
+	c.mu.Unlock()
+
+	c.added <- struct{}{}
+	return t
+}
+
+// Advance moves the clock forward, firing expired timers.
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+	for _, t := range c.timers {
+		t.fireIfDue()
+	}
+}
+
+// fakeTimer is a Timer driven by a fakeClock; its methods require the
+// clock's lock unless noted otherwise.
+type fakeTimer struct {
+	clock  *fakeClock
+	when   time.Time
+	active bool
+	ch     chan time.Time
+}
+
+func (t *fakeTimer) schedule(d time.Duration) {
+	t.when = t.clock.now.Add(d)
+	t.active = true
+	t.fireIfDue()
+}
+
+func (t *fakeTimer) fireIfDue() {
+	if t.active && !t.when.After(t.clock.now) {
+		t.active = false
+		select {
+		case t.ch <- t.clock.now:
+		default:
+		}
+	}
+}
+
+func (t *fakeTimer) C() <-chan time.Time { return t.ch }
+
+func (t *fakeTimer) Stop() bool {
+	t.clock.mu.Lock()
+	defer t.clock.mu.Unlock()
+	was := t.active
+	t.active = false
+	return was
+}
+
+func (t *fakeTimer) Reset(d time.Duration) bool {
+	t.clock.mu.Lock()
+	was := t.active
+	t.schedule(d)
+	t.clock.mu.Unlock()
+
+	t.clock.added <- struct{}{}
+	return was
+}
+
+func TestWithClock_Wait(t *testing.T) {
+	clk := newFakeClock()
+	b := New(1*time.Hour, 2.0, 4*time.Hour, WithJitter(false), WithClock(clk))
+
+	errc := make(chan error, 1)
+	go func() { errc <- b.Wait(context.Background()) }()
+
+	<-clk.added
+	clk.Advance(59 * time.Minute)
+	select {
+	case err := <-errc:
+		t.Fatalf("Wait() returned early with %v", err)
+	default:
+	}
+
+	clk.Advance(1 * time.Minute)
+	if err := <-errc; err != nil {
+		t.Errorf("Wait() error = %v, want nil", err)
+	}
+}
+
+func TestWithClock_WaitChan(t *testing.T) {
+	clk := newFakeClock()
+	b := New(1*time.Hour, 2.0, 4*time.Hour, WithJitter(false), WithClock(clk))
+
+	for i, want := range []time.Duration{1 * time.Hour, 2 * time.Hour} {
+		ch := b.WaitChan()
+		<-clk.added
+		if got := b.Attempt(); got != i+1 {
+			t.Errorf("Attempt() after WaitChan() = %d, want %d", got, i+1)
+		}
+
+		clk.Advance(want - time.Minute)
+		select {
+		case <-ch:
+			t.Fatalf("wait %d: channel closed early", i+1)
+		default:
+		}
+
+		clk.Advance(time.Minute)
+		<-ch
+	}
+
+	// a zero delay closes the channel right away, with no timer
+	b = New(1*time.Hour, 2.0, 4*time.Hour, WithJitter(false), WithClock(clk), WithImmediateFirst())
+	select {
+	case <-b.WaitChan():
+	default:
+		t.Error("WaitChan() for a zero delay is not closed")
+	}
+	select {
+	case <-clk.added:
+		t.Error("WaitChan() for a zero delay created a timer")
+	default:
+	}
+}
+
+func TestWithClock_Stop(t *testing.T) {
+	clk := newFakeClock()
+	b := New(1*time.Second, 2.0, 1*time.Minute, WithClock(clk), WithMaxElapsedTime(30*time.Second))
+
+	b.Next()
+	clk.Advance(30 * time.Second)
+	if b.Stop() {
+		t.Errorf("Stop() at exactly the budget = true, want false")
+	}
+	clk.Advance(1 * time.Nanosecond)
+	if !b.Stop() {
+		t.Errorf("Stop() past the budget = false, want true")
+	}
+}
+
+func TestWithClock_Elapsed(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []Option
+	}{
+		{"with jitter", nil},
+		// no jitter takes the lock-free path in Next
+		{"without jitter", []Option{WithJitter(false)}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clk := newFakeClock()
+			b := New(1*time.Second, 2.0, 1*time.Minute, append(tt.opts, WithClock(clk))...)
+
+			clk.Advance(5 * time.Second)
+			if got := b.Elapsed(); got != 0 {
+				t.Errorf("Elapsed() before Next() = %v, want 0", got)
+			}
+
+			b.Next()
+			clk.Advance(3 * time.Second)
+			b.Next()
+			clk.Advance(4 * time.Second)
+			if got := b.Elapsed(); got != 7*time.Second {
+				t.Errorf("Elapsed() = %v, want %v", got, 7*time.Second)
+			}
+
+			b.Reset()
+			if got := b.Elapsed(); got != 0 {
+				t.Errorf("Elapsed() after Reset() = %v, want 0", got)
+			}
+			b.Next()
+			clk.Advance(2 * time.Second)
+			if got := b.Elapsed(); got != 2*time.Second {
+				t.Errorf("Elapsed() after Reset() and Next() = %v, want %v", got, 2*time.Second)
+			}
+		})
+	}
+}
+
+func TestRealClock(t *testing.T) {
+	var c Clock = realClock{}
+	if d := time.Since(c.Now()); d < 0 || d > time.Second {
+		t.Errorf("realClock.Now() is off by %v", d)
+	}
+
+	timer := c.NewTimer(time.Hour)
+	if !timer.Stop() {
+		t.Errorf("Stop() on active timer = false, want true")
+	}
+	timer.Reset(1 * time.Millisecond)
+	<-timer.C()
+}
+
+func TestWithClock_WaitN(t *testing.T) {
+	type step struct {
+		i int
+		d time.Duration
+	}
+
+	tests := []struct {
+		name     string
+		n        int
+		cancelAt int // cancel once this many waits are in progress (0 = never)
+		want     []step
+		wantErr  error
+		wantMsg  string
+	}{
+		{
+			name: "all waits",
+			n:    3,
+			want: []step{{1, 1 * time.Second}, {2, 2 * time.Second}, {3, 4 * time.Second}},
+		},
+		{
+			name: "zero waits",
+			n:    0,
+		},
+		{
+			name:     "canceled mid-run",
+			n:        4,
+			cancelAt: 3,
+			want:     []step{{1, 1 * time.Second}, {2, 2 * time.Second}, {3, 4 * time.Second}},
+			wantErr:  context.Canceled,
+			wantMsg:  "backoff: WaitN interrupted after 2 of 4 waits: context canceled",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clk := newFakeClock()
+			b := New(1*time.Second, 2.0, 1*time.Minute, WithJitter(false), WithClock(clk))
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			var (
+				mu  sync.Mutex
+				got []step
+			)
+			errc := make(chan error, 1)
+			go func() {
+				errc <- b.WaitN(ctx, tt.n, func(i int, d time.Duration) {
+					mu.Lock()
+					got = append(got, step{i, d})
+					mu.Unlock()
+				})
+			}()
+
+			for i := 1; i <= tt.n; i++ {
+				<-clk.added
+				if i == tt.cancelAt {
+					cancel()
+					break
+				}
+				clk.Advance(time.Duration(1<<(i-1)) * time.Second)
+			}
+
+			err := <-errc
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("WaitN() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantMsg != "" && err.Error() != tt.wantMsg {
+				t.Errorf("WaitN() error = %q, want %q", err, tt.wantMsg)
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("progress calls = %v, want %v", got, tt.want)
+			}
+			if got := b.Attempt(); got != len(tt.want) {
+				t.Errorf("Attempt() = %d, want %d", got, len(tt.want))
+			}
+		})
+	}
+}
+
+func TestWithClock_SetFloorHint(t *testing.T) {
+	const ms = time.Millisecond
+
+	tests := []struct {
+		name string
+		opts []Option
+	}{
+		{"lock-free path", []Option{WithJitter(false)}},
+		{"mutex path", []Option{WithJitter(false), WithMaxTotalDelay(time.Hour)}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clk := newFakeClock()
+			b := New(100*ms, 2.0, 1*time.Second, append(tt.opts, WithClock(clk))...)
+
+			b.SetFloorHint(500*ms, 10*time.Second)
+			// a lower hint does not replace the active one
+			b.SetFloorHint(300*ms, 1*time.Hour)
+			// invalid hints are ignored
+			b.SetFloorHint(5*time.Second, 0)
+			b.SetFloorHint(-1, time.Hour)
+
+			want := []time.Duration{500 * ms, 500 * ms, 500 * ms, 800 * ms}
+			for i, w := range want {
+				if d := b.Next(); d != w {
+					t.Errorf("Next() call %d = %v, want %v", i+1, d, w)
+				}
+			}
+
+			// a hint above max still applies
+			b.SetFloorHint(3*time.Second, 5*time.Second)
+			if d := b.Next(); d != 3*time.Second {
+				t.Errorf("Next() with hint above max = %v, want %v", d, 3*time.Second)
+			}
+
+			// Reset keeps the hint; only time expires it
+			b.Reset()
+			clk.Advance(5*time.Second - 1)
+			if d := b.Next(); d != 3*time.Second {
+				t.Errorf("Next() just before expiry = %v, want %v", d, 3*time.Second)
+			}
+			clk.Advance(1)
+			if d := b.Next(); d != 200*ms {
+				t.Errorf("Next() after expiry = %v, want %v", d, 200*ms)
+			}
+		})
+	}
+}
+
+func TestWithClock_NextDeadline(t *testing.T) {
+	clk := newFakeClock()
+	start := clk.Now()
+	b := New(1*time.Second, 2.0, 1*time.Minute, WithJitter(false), WithClock(clk))
+
+	tests := []struct {
+		advance time.Duration
+		want    time.Time
+	}{
+		{0, start.Add(1 * time.Second)},
+		{0, start.Add(2 * time.Second)},
+		{10 * time.Second, start.Add(10*time.Second + 4*time.Second)},
+	}
+
+	for i, tt := range tests {
+		clk.Advance(tt.advance)
+		if got := b.NextDeadline(); !got.Equal(tt.want) {
+			t.Errorf("NextDeadline() call %d = %v, want %v", i+1, got, tt.want)
+		}
+	}
+	if got := b.Attempt(); got != len(tests) {
+		t.Errorf("Attempt() = %d, want %d", got, len(tests))
+	}
+}
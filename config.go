@@ -0,0 +1,167 @@
+package backoff
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+)
+
+// Config é a configuração serializável de um Backoff. Em JSON as durações
+// usam o formato de time.ParseDuration, por exemplo "100ms".
+type Config struct {
+	Initial      time.Duration
+	Factor       float64
+	Max          time.Duration
+	Jitter       JitterStrategy
+	JitterFactor float64 // usado apenas com ProportionalJitter e UpwardJitter
+}
+
+// configJSON é a representação JSON de Config.
+type configJSON struct {
+	Initial      string         `json:"initial"`
+	Factor       float64        `json:"factor"`
+	Max          string         `json:"max"`
+	Jitter       JitterStrategy `json:"jitter"`
+	JitterFactor float64        `json:"jitter_factor,omitempty"`
+}
+
+// MarshalJSON implementa json.Marshaler.
+func (c Config) MarshalJSON() ([]byte, error) {
+	return json.Marshal(configJSON{
+		Initial:      c.Initial.String(),
+		Factor:       c.Factor,
+		Max:          c.Max.String(),
+		Jitter:       c.Jitter,
+		JitterFactor: c.JitterFactor,
+	})
+}
+
+// UnmarshalJSON implementa json.Unmarshaler. Retorna erro se factor < 1.0.
+func (c *Config) UnmarshalJSON(data []byte) error {
+	var v configJSON
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+
+	initial, err := time.ParseDuration(v.Initial)
+	if err != nil {
+		return fmt.Errorf("backoff: invalid initial: %w", err)
+	}
+	max, err := time.ParseDuration(v.Max)
+	if err != nil {
+		return fmt.Errorf("backoff: invalid max: %w", err)
+	}
+	if err := validateFactor(v.Factor); err != nil {
+		return err
+	}
+
+	*c = Config{
+		Initial:      initial,
+		Factor:       v.Factor,
+		Max:          max,
+		Jitter:       v.Jitter,
+		JitterFactor: v.JitterFactor,
+	}
+	return nil
+}
+
+// ToBackoff cria um Backoff com esta configuração; opts são aplicadas em
+// seguida.
+func (c Config) ToBackoff(opts ...Option) *Backoff {
+	base := []Option{WithJitterStrategy(c.Jitter)}
+	if c.Jitter == ProportionalJitter || c.Jitter == UpwardJitter {
+		base = append(base, WithJitterFactor(c.JitterFactor))
+	}
+	return New(c.Initial, c.Factor, c.Max, append(base, opts...)...)
+}
+
+// Config retorna a configuração serializável do Backoff.
+func (b *Backoff) Config() Config {
+	b.setup()
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	c := Config{
+		Initial: b.initial,
+		Factor:  b.growthFactor(),
+		Max:     b.maxValue(),
+		Jitter:  b.strategy,
+	}
+	if b.strategy == ProportionalJitter || b.strategy == UpwardJitter {
+		c.JitterFactor = b.jitterFrac
+	}
+	return c
+}
+
+// FromEnv cria um Backoff a partir de variáveis de ambiente, para configurar
+// sem recompilar: PREFIX_INITIAL e PREFIX_MAX no formato de
+// time.ParseDuration ("100ms"), PREFIX_FACTOR como número ("2.0") e
+// PREFIX_JITTER como booleano ("true" usa FullJitter, "false" NoJitter) ou
+// nome de estratégia ("equal"). Variáveis ausentes ou vazias usam os padrões
+// do valor zero: 100ms, 2.0, 30s e FullJitter. Com prefix vazio os nomes não
+// têm prefixo. Retorna erro se algum valor for malformado ou se a
+// configuração resultante for inválida, como em NewValidated; opts são
+// aplicadas em seguida.
+func FromEnv(prefix string, opts ...Option) (*Backoff, error) {
+	initial, max := 100*time.Millisecond, 30*time.Second
+	factor, strategy := 2.0, FullJitter
+
+	var err error
+	if v, key := env(prefix, "INITIAL"); v != "" {
+		if initial, err = time.ParseDuration(v); err != nil {
+			return nil, fmt.Errorf("backoff: invalid %s=%q: %w", key, v, err)
+		}
+	}
+	if v, key := env(prefix, "FACTOR"); v != "" {
+		if factor, err = strconv.ParseFloat(v, 64); err != nil {
+			return nil, fmt.Errorf("backoff: invalid %s=%q: %w", key, v, err)
+		}
+	}
+	if v, key := env(prefix, "MAX"); v != "" {
+		if max, err = time.ParseDuration(v); err != nil {
+			return nil, fmt.Errorf("backoff: invalid %s=%q: %w", key, v, err)
+		}
+	}
+	if v, key := env(prefix, "JITTER"); v != "" {
+		if on, err := strconv.ParseBool(v); err == nil {
+			strategy = NoJitter
+			if on {
+				strategy = FullJitter
+			}
+		} else if err := strategy.UnmarshalText([]byte(v)); err != nil {
+			return nil, fmt.Errorf("backoff: invalid %s=%q: want a boolean or a jitter strategy name", key, v)
+		}
+	}
+
+	return NewValidated(initial, factor, max, append([]Option{WithJitterStrategy(strategy)}, opts...)...)
+}
+
+// env retorna o valor da variável prefix_name e o nome usado.
+func env(prefix, name string) (string, string) {
+	if prefix != "" {
+		name = prefix + "_" + name
+	}
+	return os.Getenv(name), name
+}
+
+// MarshalText implementa encoding.TextMarshaler usando os nomes de String.
+func (s JitterStrategy) MarshalText() ([]byte, error) {
+	switch s {
+	case FullJitter, Decorrelated, NoJitter, EqualJitter, ProportionalJitter, UpwardJitter:
+		return []byte(s.String()), nil
+	}
+	return nil, fmt.Errorf("backoff: unknown jitter strategy %d", int(s))
+}
+
+// UnmarshalText implementa encoding.TextUnmarshaler.
+func (s *JitterStrategy) UnmarshalText(text []byte) error {
+	for _, v := range []JitterStrategy{FullJitter, Decorrelated, NoJitter, EqualJitter, ProportionalJitter, UpwardJitter} {
+		if v.String() == string(text) {
+			*s = v
+			return nil
+		}
+	}
+	return fmt.Errorf("backoff: unknown jitter strategy %q", text)
+}
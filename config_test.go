@@ -0,0 +1,182 @@
+package backoff
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestConfig_JSONRoundTrip(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  Config
+		want string
+	}{
+		{
+			name: "full jitter",
+			cfg:  Config{Initial: 100 * time.Millisecond, Factor: 2.0, Max: 5 * time.Second, Jitter: FullJitter},
+			want: `{"initial":"100ms","factor":2,"max":"5s","jitter":"full"}`,
+		},
+		{
+			name: "proportional jitter",
+			cfg:  Config{Initial: 1 * time.Second, Factor: 1.5, Max: 1 * time.Minute, Jitter: ProportionalJitter, JitterFactor: 0.2},
+			want: `{"initial":"1s","factor":1.5,"max":"1m0s","jitter":"proportional","jitter_factor":0.2}`,
+		},
+		{
+			name: "upward jitter",
+			cfg:  Config{Initial: 1 * time.Second, Factor: 2.0, Max: 1 * time.Minute, Jitter: UpwardJitter, JitterFactor: 0.5},
+			want: `{"initial":"1s","factor":2,"max":"1m0s","jitter":"upward","jitter_factor":0.5}`,
+		},
+		{
+			name: "no jitter",
+			cfg:  Config{Initial: 250 * time.Millisecond, Factor: 3.0, Max: 10 * time.Second, Jitter: NoJitter},
+			want: `{"initial":"250ms","factor":3,"max":"10s","jitter":"none"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := json.Marshal(tt.cfg)
+			if err != nil {
+				t.Fatalf("json.Marshal() error = %v", err)
+			}
+			if string(data) != tt.want {
+				t.Errorf("json.Marshal() = %s, want %s", data, tt.want)
+			}
+
+			var got Config
+			if err := json.Unmarshal(data, &got); err != nil {
+				t.Fatalf("json.Unmarshal() error = %v", err)
+			}
+			if got != tt.cfg {
+				t.Errorf("round trip = %+v, want %+v", got, tt.cfg)
+			}
+
+			// Building a backoff and reading its config back must match too
+			if back := got.ToBackoff().Config(); back != tt.cfg {
+				t.Errorf("ToBackoff().Config() = %+v, want %+v", back, tt.cfg)
+			}
+		})
+	}
+}
+
+func TestConfig_UnmarshalErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+	}{
+		{"factor below 1", `{"initial":"100ms","factor":0.5,"max":"5s","jitter":"full"}`},
+		{"missing factor", `{"initial":"100ms","max":"5s","jitter":"full"}`},
+		{"bad initial", `{"initial":"soon","factor":2,"max":"5s","jitter":"full"}`},
+		{"bad max", `{"initial":"100ms","factor":2,"max":"","jitter":"full"}`},
+		{"unknown jitter", `{"initial":"100ms","factor":2,"max":"5s","jitter":"wild"}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var c Config
+			if err := json.Unmarshal([]byte(tt.data), &c); err == nil {
+				t.Errorf("json.Unmarshal(%s) should fail", tt.data)
+			}
+		})
+	}
+}
+
+func TestFromEnv(t *testing.T) {
+	tests := []struct {
+		name    string
+		env     map[string]string
+		want    Config
+		wantErr string
+	}{
+		{
+			name: "all set",
+			env:  map[string]string{"RETRY_INITIAL": "250ms", "RETRY_FACTOR": "1.5", "RETRY_MAX": "1m", "RETRY_JITTER": "false"},
+			want: Config{Initial: 250 * time.Millisecond, Factor: 1.5, Max: time.Minute, Jitter: NoJitter},
+		},
+		{
+			name: "missing keys use defaults",
+			env:  map[string]string{"RETRY_MAX": "10s"},
+			want: Config{Initial: 100 * time.Millisecond, Factor: 2.0, Max: 10 * time.Second, Jitter: FullJitter},
+		},
+		{
+			name: "empty values use defaults",
+			env:  map[string]string{"RETRY_INITIAL": "", "RETRY_JITTER": ""},
+			want: Config{Initial: 100 * time.Millisecond, Factor: 2.0, Max: 30 * time.Second, Jitter: FullJitter},
+		},
+		{
+			name: "jitter enabled",
+			env:  map[string]string{"RETRY_JITTER": "true"},
+			want: Config{Initial: 100 * time.Millisecond, Factor: 2.0, Max: 30 * time.Second, Jitter: FullJitter},
+		},
+		{
+			name: "jitter strategy name",
+			env:  map[string]string{"RETRY_JITTER": "equal"},
+			want: Config{Initial: 100 * time.Millisecond, Factor: 2.0, Max: 30 * time.Second, Jitter: EqualJitter},
+		},
+		{
+			name:    "malformed initial",
+			env:     map[string]string{"RETRY_INITIAL": "fast"},
+			wantErr: `backoff: invalid RETRY_INITIAL="fast"`,
+		},
+		{
+			name:    "malformed factor",
+			env:     map[string]string{"RETRY_FACTOR": "two"},
+			wantErr: `backoff: invalid RETRY_FACTOR="two"`,
+		},
+		{
+			name:    "malformed max",
+			env:     map[string]string{"RETRY_MAX": "30"},
+			wantErr: `backoff: invalid RETRY_MAX="30"`,
+		},
+		{
+			name:    "malformed jitter",
+			env:     map[string]string{"RETRY_JITTER": "maybe"},
+			wantErr: `backoff: invalid RETRY_JITTER="maybe"`,
+		},
+		{
+			name:    "invalid configuration",
+			env:     map[string]string{"RETRY_FACTOR": "0.5"},
+			wantErr: "factor",
+		},
+		{
+			name:    "max below initial",
+			env:     map[string]string{"RETRY_INITIAL": "1s", "RETRY_MAX": "500ms"},
+			wantErr: "max (500ms) must not be less than initial (1s)",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, key := range []string{"RETRY_INITIAL", "RETRY_FACTOR", "RETRY_MAX", "RETRY_JITTER"} {
+				t.Setenv(key, tt.env[key])
+			}
+
+			b, err := FromEnv("RETRY")
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("FromEnv() error = %v, want it to contain %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("FromEnv() error = %v", err)
+			}
+			if got := b.Config(); got != tt.want {
+				t.Errorf("FromEnv().Config() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+
+	// without a prefix the names are bare
+	t.Setenv("INITIAL", "2s")
+	t.Setenv("MAX", "4s")
+	b, err := FromEnv("", WithJitter(false))
+	if err != nil {
+		t.Fatalf("FromEnv(\"\") error = %v", err)
+	}
+	if got := b.Next(); got != 2*time.Second {
+		t.Errorf("FromEnv(\"\").Next() = %v, want %v", got, 2*time.Second)
+	}
+}
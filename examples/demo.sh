@@ -45,6 +45,16 @@ echo
 cd ../http-retry && go run main.go
 echo
 
+echo "----------------------------------------"
+echo
+
+echo "5. Reconnection Loop:"
+echo "   Demonstrates reconnecting until success, then starting cheap again"
+echo "   Expected: Two refusals, a connection, then one refusal and a reconnection"
+echo
+cd ../reconnect && go run main.go
+echo
+
 echo "========================================"
 echo "Demo completed!"
 echo "========================================"
@@ -21,7 +21,13 @@ func fetchWithRetry(url string, maxRetries int) (*http.Response, error) {
 		}
 
 		if i < maxRetries-1 {
-			wait := b.Next()
+			// Honor the server's Retry-After hint, keeping the exponential floor
+			var hint time.Duration
+			if err == nil {
+				hint, _ = backoff.ParseRetryAfter(resp.Header.Get("Retry-After"), time.Now())
+				resp.Body.Close()
+			}
+			wait := b.NextAfter(hint)
 			fmt.Printf("Request failed, retrying in %v...\n", wait)
 			time.Sleep(wait)
 		}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/crgimenes/backoff"
+)
+
+// db simulates a database that refuses the first connections.
+type db struct {
+	refusals int
+}
+
+func (d *db) connect(ctx context.Context) error {
+	if d.refusals > 0 {
+		d.refusals--
+		fmt.Println("  connect: connection refused")
+		return errors.New("connection refused")
+	}
+	fmt.Println("  connect: connected")
+	return nil
+}
+
+func main() {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	b := backoff.New(100*time.Millisecond, 2.0, 2*time.Second, backoff.WithJitter(false))
+	conn := &db{refusals: 2}
+
+	fmt.Println("Connecting:")
+	if err := backoff.ReconnectLoop(ctx, b, conn.connect); err != nil {
+		fmt.Printf("Giving up: %v\n", err)
+		return
+	}
+
+	// The connection drops later; the backoff was reset, so retries start cheap
+	conn.refusals = 1
+	fmt.Println("\nConnection lost, reconnecting:")
+	if err := backoff.ReconnectLoop(ctx, b, conn.connect); err != nil {
+		fmt.Printf("Giving up: %v\n", err)
+		return
+	}
+}
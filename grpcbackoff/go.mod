@@ -0,0 +1,20 @@
+module github.com/crgimenes/backoff/grpcbackoff
+
+go 1.25
+
+require (
+	github.com/crgimenes/backoff v0.0.0-20261016084952-4fb150df0752
+	google.golang.org/grpc v1.75.0
+)
+
+require (
+	golang.org/x/net v0.41.0 // indirect
+	golang.org/x/sys v0.33.0 // indirect
+	golang.org/x/text v0.26.0 // indirect
+	google.golang.org/genproto/googleapis/rpc v0.0.0-20250707201910-8d1bb00bc6a7 // indirect
+	google.golang.org/protobuf v1.36.6 // indirect
+)
+
+// desenvolvimento local: usa o módulo raiz deste repositório; replace é
+// ignorado quando grpcbackoff é usado como dependência
+replace github.com/crgimenes/backoff => ../
@@ -0,0 +1,49 @@
+// Package grpcbackoff integra backoff a clientes gRPC. Fica em um módulo
+// separado para que o pacote backoff continue sem dependências.
+package grpcbackoff
+
+import (
+	"context"
+	"errors"
+	"slices"
+
+	"github.com/crgimenes/backoff"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// DefaultRetryCodes são os códigos repetidos por UnaryClientInterceptor
+// quando nenhum é informado.
+var DefaultRetryCodes = []codes.Code{
+	codes.Unavailable,
+	codes.ResourceExhausted,
+}
+
+// UnaryClientInterceptor repete chamadas unárias que falham com um dos códigos
+// retryable (DefaultRetryCodes se vazio), esperando os intervalos de b. Cada
+// chamada usa um clone de b, então chamadas concorrentes não compartilham
+// estado. As tentativas param quando o contexto da chamada termina, os limites
+// verificados por NextOK são atingidos ou o erro não é repetível; o erro
+// retornado é o da última tentativa.
+func UnaryClientInterceptor(b *backoff.Backoff, retryable ...codes.Code) grpc.UnaryClientInterceptor {
+	if len(retryable) == 0 {
+		retryable = DefaultRetryCodes
+	}
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		err := backoff.Do(ctx, b.Clone(), func() error {
+			err := invoker(ctx, method, req, reply, cc, opts...)
+			if err != nil && !slices.Contains(retryable, status.Code(err)) {
+				return backoff.Permanent(err)
+			}
+			return err
+		}, nil)
+
+		// devolve o erro original para que status.Code continue funcionando
+		var perm *backoff.PermanentError
+		if errors.As(err, &perm) {
+			return perm.Err
+		}
+		return err
+	}
+}
@@ -0,0 +1,95 @@
+package grpcbackoff
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/crgimenes/backoff"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// failingInvoker returns a grpc.UnaryInvoker that fails with code for the
+// first failures calls and succeeds afterwards, counting every call.
+func failingInvoker(code codes.Code, failures int, calls *int) grpc.UnaryInvoker {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+		*calls++
+		if *calls <= failures {
+			return status.Error(code, "try again")
+		}
+		return nil
+	}
+}
+
+func TestUnaryClientInterceptor(t *testing.T) {
+	tests := []struct {
+		name      string
+		code      codes.Code
+		retryable []codes.Code
+		wantCode  codes.Code
+		wantCalls int
+	}{
+		{"unavailable fails twice", codes.Unavailable, nil, codes.OK, 3},
+		{"resource exhausted fails twice", codes.ResourceExhausted, nil, codes.OK, 3},
+		{"non-retryable code", codes.InvalidArgument, nil, codes.InvalidArgument, 1},
+		{"custom codes", codes.Aborted, []codes.Code{codes.Aborted}, codes.OK, 3},
+		{"custom codes exclude defaults", codes.Unavailable, []codes.Code{codes.Aborted}, codes.Unavailable, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := backoff.New(1*time.Millisecond, 2.0, 5*time.Millisecond, backoff.WithJitter(false))
+			interceptor := UnaryClientInterceptor(b, tt.retryable...)
+
+			calls := 0
+			err := interceptor(context.Background(), "/svc/Method", nil, nil, nil, failingInvoker(tt.code, 2, &calls))
+			if got := status.Code(err); got != tt.wantCode {
+				t.Errorf("interceptor() code = %v, want %v (err %v)", got, tt.wantCode, err)
+			}
+			if calls != tt.wantCalls {
+				t.Errorf("invoker calls = %d, want %d", calls, tt.wantCalls)
+			}
+			// the interceptor works on a clone
+			if got := b.Attempt(); got != 0 {
+				t.Errorf("Attempt() of the shared backoff = %d, want 0", got)
+			}
+		})
+	}
+}
+
+func TestUnaryClientInterceptor_MaxAttempts(t *testing.T) {
+	b := backoff.New(1*time.Millisecond, 1.0, 1*time.Millisecond, backoff.WithMaxAttempts(2))
+	interceptor := UnaryClientInterceptor(b)
+
+	calls := 0
+	err := interceptor(context.Background(), "/svc/Method", nil, nil, nil, failingInvoker(codes.Unavailable, 10, &calls))
+	if got := status.Code(err); got != codes.Unavailable {
+		t.Errorf("interceptor() code = %v, want %v", got, codes.Unavailable)
+	}
+	if calls != 3 {
+		t.Errorf("invoker calls = %d, want 3", calls)
+	}
+}
+
+func TestUnaryClientInterceptor_ContextDeadline(t *testing.T) {
+	b := backoff.New(1*time.Hour, 2.0, 1*time.Hour, backoff.WithJitter(false))
+	interceptor := UnaryClientInterceptor(b)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	calls := 0
+	start := time.Now()
+	err := interceptor(ctx, "/svc/Method", nil, nil, nil, failingInvoker(codes.Unavailable, 10, &calls))
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("interceptor() took %v, want to stop at the deadline", elapsed)
+	}
+	if got := status.Code(err); got != codes.Unavailable {
+		t.Errorf("interceptor() code = %v, want %v", got, codes.Unavailable)
+	}
+	if calls != 1 {
+		t.Errorf("invoker calls = %d, want 1", calls)
+	}
+}
@@ -0,0 +1,126 @@
+package backoff
+
+import (
+	"errors"
+	"io"
+	"math"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ParseRetryAfter interpreta o cabeçalho Retry-After nas formas delta-seconds
+// e HTTP-date, retornando a espera sugerida em relação a now. Datas no
+// passado resultam em zero e esperas maiores que o maior Duration saturam
+// nele, de modo que o resultado nunca é negativo. Retorna false se o valor
+// for vazio ou inválido.
+func ParseRetryAfter(h string, now time.Time) (time.Duration, bool) {
+	h = strings.TrimSpace(h)
+	if h == "" {
+		return 0, false
+	}
+
+	secs, err := strconv.ParseInt(h, 10, 64)
+	switch {
+	case err == nil && secs < 0:
+		return 0, false
+	case err == nil && secs > math.MaxInt64/int64(time.Second):
+		return math.MaxInt64, true
+	case err == nil:
+		return time.Duration(secs) * time.Second, true
+	case errors.Is(err, strconv.ErrRange) && secs > 0:
+		// mais dígitos do que cabem em int64
+		return math.MaxInt64, true
+	}
+
+	t, err := http.ParseTime(h)
+	if err != nil {
+		return 0, false
+	}
+	d := t.Sub(now)
+	if d < 0 {
+		d = 0
+	}
+	return d, true
+}
+
+// DefaultRetryStatusCodes são os códigos repetidos por Transport quando
+// StatusCodes é nil.
+var DefaultRetryStatusCodes = []int{
+	http.StatusTooManyRequests,
+	http.StatusInternalServerError,
+	http.StatusBadGateway,
+	http.StatusServiceUnavailable,
+	http.StatusGatewayTimeout,
+}
+
+// Transport é um http.RoundTripper que repete requisições com backoff em
+// erros de rede e nos códigos de StatusCodes, respeitando Retry-After e o
+// contexto da requisição. Cada requisição usa um clone de Backoff, então
+// requisições concorrentes não compartilham estado. O corpo é reenviado via
+// GetBody; sem ele a requisição não é repetida.
+type Transport struct {
+	Base        http.RoundTripper // nil = http.DefaultTransport
+	Backoff     *Backoff          // política de espera entre tentativas
+	StatusCodes []int             // nil = DefaultRetryStatusCodes
+}
+
+// RoundTrip implementa http.RoundTripper.
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	b := t.Backoff.Clone()
+	ctx := req.Context()
+
+	r := req
+	for {
+		resp, err := base.RoundTrip(r)
+		if err == nil && !t.retryable(resp.StatusCode) {
+			return resp, nil
+		}
+		if ctx.Err() != nil {
+			return resp, err
+		}
+		if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+			return resp, err
+		}
+
+		d, ok := b.NextOK()
+		if !ok {
+			return resp, err
+		}
+		if resp != nil {
+			if hint, ok := ParseRetryAfter(resp.Header.Get("Retry-After"), b.getClock().Now()); ok {
+				d = max(d, hint)
+			}
+			io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}
+
+		if err := b.sleep(ctx, d); err != nil {
+			return nil, err
+		}
+
+		r = req.Clone(ctx)
+		if req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			r.Body = body
+		}
+	}
+}
+
+// retryable informa se o código deve ser repetido.
+func (t *Transport) retryable(code int) bool {
+	codes := t.StatusCodes
+	if codes == nil {
+		codes = DefaultRetryStatusCodes
+	}
+	return slices.Contains(codes, code)
+}
@@ -0,0 +1,193 @@
+package backoff
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2024, time.January, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name   string
+		header string
+		want   time.Duration
+		wantOK bool
+	}{
+		{"empty", "", 0, false},
+		{"delta seconds", "120", 120 * time.Second, true},
+		{"delta seconds with spaces", " 5 ", 5 * time.Second, true},
+		{"zero seconds", "0", 0, true},
+		{"negative seconds", "-1", 0, false},
+		{"largest seconds without overflow", "9223372036", 9223372036 * time.Second, true},
+		{"seconds overflowing Duration", "9999999999", math.MaxInt64, true},
+		{"seconds far beyond Duration", "99999999999", math.MaxInt64, true},
+		{"seconds overflowing int64", "99999999999999999999", math.MaxInt64, true},
+		{"negative seconds overflowing int64", "-99999999999999999999", 0, false},
+		{"http date", "Mon, 01 Jan 2024 12:00:30 GMT", 30 * time.Second, true},
+		{"http date in the past", "Mon, 01 Jan 2024 11:59:00 GMT", 0, true},
+		{"invalid", "soon", 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := ParseRetryAfter(tt.header, now)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("ParseRetryAfter(%q) = (%v, %v), want (%v, %v)", tt.header, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestTransport(t *testing.T) {
+	tests := []struct {
+		name       string
+		failStatus int
+		failures   int32
+		codes      []int
+		maxAttempt int
+		wantStatus int
+		wantCalls  int32
+	}{
+		{
+			name:       "fails twice then succeeds",
+			failStatus: http.StatusServiceUnavailable,
+			failures:   2,
+			wantStatus: http.StatusOK,
+			wantCalls:  3,
+		},
+		{
+			name:       "non-retryable status",
+			failStatus: http.StatusBadRequest,
+			failures:   2,
+			wantStatus: http.StatusBadRequest,
+			wantCalls:  1,
+		},
+		{
+			name:       "custom status codes",
+			failStatus: http.StatusConflict,
+			failures:   1,
+			codes:      []int{http.StatusConflict},
+			wantStatus: http.StatusOK,
+			wantCalls:  2,
+		},
+		{
+			name:       "attempts exhausted returns last response",
+			failStatus: http.StatusInternalServerError,
+			failures:   10,
+			maxAttempt: 2,
+			wantStatus: http.StatusInternalServerError,
+			wantCalls:  3,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls atomic.Int32
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, _ := io.ReadAll(r.Body)
+				if string(body) != "payload" {
+					t.Errorf("request %d body = %q, want %q", calls.Load()+1, body, "payload")
+				}
+				if calls.Add(1) <= tt.failures {
+					w.Header().Set("Retry-After", "0")
+					w.WriteHeader(tt.failStatus)
+					return
+				}
+				w.WriteHeader(http.StatusOK)
+			}))
+			defer srv.Close()
+
+			client := &http.Client{Transport: &Transport{
+				Backoff:     New(1*time.Millisecond, 2.0, 10*time.Millisecond, WithMaxAttempts(tt.maxAttempt)),
+				StatusCodes: tt.codes,
+			}}
+
+			resp, err := client.Post(srv.URL, "text/plain", strings.NewReader("payload"))
+			if err != nil {
+				t.Fatalf("Post() error = %v", err)
+			}
+			resp.Body.Close()
+
+			if resp.StatusCode != tt.wantStatus {
+				t.Errorf("status = %d, want %d", resp.StatusCode, tt.wantStatus)
+			}
+			if got := calls.Load(); got != tt.wantCalls {
+				t.Errorf("server received %d requests, want %d", got, tt.wantCalls)
+			}
+		})
+	}
+}
+
+func TestTransport_ContextCanceled(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	client := &http.Client{Transport: &Transport{
+		Backoff: New(1*time.Hour, 2.0, 2*time.Hour, WithJitter(false)),
+	}}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL, nil)
+
+	_, err := client.Do(req)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Do() error = %v, want %v", err, context.DeadlineExceeded)
+	}
+}
+
+func TestTransport_RetryAfter(t *testing.T) {
+	clk := newFakeClock()
+	var calls atomic.Int32
+	base := roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		if calls.Add(1) == 1 {
+			return &http.Response{
+				StatusCode: http.StatusTooManyRequests,
+				Header:     http.Header{"Retry-After": []string{"30"}},
+				Body:       http.NoBody,
+			}, nil
+		}
+		return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody}, nil
+	})
+
+	tr := &Transport{
+		Base:    base,
+		Backoff: New(1*time.Millisecond, 2.0, 10*time.Millisecond, WithClock(clk)),
+	}
+
+	done := make(chan *http.Response)
+	go func() {
+		req, _ := http.NewRequest(http.MethodGet, "http://example.com", nil)
+		resp, _ := tr.RoundTrip(req)
+		done <- resp
+	}()
+
+	// The server hint dominates the 1ms backoff
+	<-clk.added
+	clk.Advance(29 * time.Second)
+	select {
+	case <-done:
+		t.Fatalf("RoundTrip() retried before Retry-After elapsed")
+	default:
+	}
+	clk.Advance(1 * time.Second)
+	if resp := <-done; resp == nil || resp.StatusCode != http.StatusOK {
+		t.Errorf("RoundTrip() = %v, want 200", resp)
+	}
+}
+
+// roundTripFunc adapts a function to http.RoundTripper.
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(r *http.Request) (*http.Response, error) { return f(r) }
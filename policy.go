@@ -0,0 +1,53 @@
+package backoff
+
+import (
+	"sync"
+	"time"
+)
+
+// Policy é o contrato mínimo de uma política de espera. Funções que aceitam
+// Policy em vez de *Backoff podem receber implementações próprias, como stubs
+// em testes.
+type Policy interface {
+	Next() time.Duration
+	Reset()
+}
+
+var _ Policy = (*Backoff)(nil)
+
+// Chain retorna uma Policy que usa first nas primeiras afterAttempts chamadas
+// a Next e second nas seguintes, por exemplo exponencial até um limite e
+// depois um intervalo constante longo. Reset reinicia as duas políticas e a
+// contagem.
+func Chain(first Policy, afterAttempts int, second Policy) Policy {
+	return &chain{first: first, second: second, after: afterAttempts}
+}
+
+// chain implementa Chain.
+type chain struct {
+	mu      sync.Mutex
+	first   Policy
+	second  Policy
+	after   int // chamadas atendidas por first
+	attempt int // chamadas a Next desde o último Reset
+}
+
+func (c *chain) Next() time.Duration {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.attempt++
+	if c.attempt <= c.after {
+		return c.first.Next()
+	}
+	return c.second.Next()
+}
+
+func (c *chain) Reset() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.first.Reset()
+	c.second.Reset()
+	c.attempt = 0
+}
@@ -0,0 +1,93 @@
+package backoff
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+// fixedPolicy is a stub Policy returning the same delay on every call.
+type fixedPolicy struct {
+	d     time.Duration
+	calls int
+}
+
+func (p *fixedPolicy) Next() time.Duration {
+	p.calls++
+	return p.d
+}
+
+func (p *fixedPolicy) Reset() { p.calls = 0 }
+
+// collect draws n delays from p and then resets it.
+func collect(p Policy, n int) []time.Duration {
+	s := make([]time.Duration, n)
+	for i := range s {
+		s[i] = p.Next()
+	}
+	p.Reset()
+	return s
+}
+
+func TestPolicy(t *testing.T) {
+	tests := []struct {
+		name   string
+		policy Policy
+		want   []time.Duration
+	}{
+		{
+			name:   "backoff",
+			policy: New(100*time.Millisecond, 2.0, 1*time.Second, WithJitter(false)),
+			want:   []time.Duration{100 * time.Millisecond, 200 * time.Millisecond, 400 * time.Millisecond},
+		},
+		{
+			name:   "stub",
+			policy: &fixedPolicy{d: 5 * time.Millisecond},
+			want:   []time.Duration{5 * time.Millisecond, 5 * time.Millisecond, 5 * time.Millisecond},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for range 2 {
+				// Reset makes the sequence repeat
+				if got := collect(tt.policy, len(tt.want)); !reflect.DeepEqual(got, tt.want) {
+					t.Errorf("collect() = %v, want %v", got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestChain(t *testing.T) {
+	first := New(100*time.Millisecond, 2.0, 1*time.Second, WithJitter(false))
+	second := Constant(5*time.Second, WithJitter(false))
+	p := Chain(first, 3, second)
+
+	want := []time.Duration{
+		100 * time.Millisecond,
+		200 * time.Millisecond,
+		400 * time.Millisecond,
+		5 * time.Second, // handoff after 3 attempts
+		5 * time.Second,
+	}
+	for i, w := range want {
+		if got := p.Next(); got != w {
+			t.Errorf("Next() call %d = %v, want %v", i+1, got, w)
+		}
+	}
+
+	// Reset restarts both policies and the handoff count
+	p.Reset()
+	if first.Attempt() != 0 || second.Attempt() != 0 {
+		t.Errorf("Attempt() after Reset() = %d, %d, want 0, 0", first.Attempt(), second.Attempt())
+	}
+	if got := collect(p, len(want)); !reflect.DeepEqual(got, want) {
+		t.Errorf("sequence after Reset() = %v, want %v", got, want)
+	}
+
+	// zero attempts goes straight to second
+	if got := Chain(first, 0, second).Next(); got != 5*time.Second {
+		t.Errorf("Chain(first, 0, second).Next() = %v, want %v", got, 5*time.Second)
+	}
+}
@@ -0,0 +1,244 @@
+package backoff
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrPermanent indica um erro que não deve ser repetido. Envolva-o com
+// fmt.Errorf("...: %w", ErrPermanent) ou use Permanent para interromper Retry
+// imediatamente.
+var ErrPermanent = errors.New("backoff: permanent error")
+
+// PermanentError envolve um erro que não deve ser repetido.
+type PermanentError struct {
+	Err error
+}
+
+// Permanent marca err como permanente. Retorna nil se err for nil.
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &PermanentError{Err: err}
+}
+
+func (e *PermanentError) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap retorna o erro original.
+func (e *PermanentError) Unwrap() error {
+	return e.Err
+}
+
+// Is faz errors.Is(err, ErrPermanent) reconhecer o wrapper.
+func (e *PermanentError) Is(target error) bool {
+	return target == ErrPermanent
+}
+
+// WithRetryIf define quais erros Retry, Do, RetryNotify, WaitLoop e
+// ReconnectLoop repetem: quando fn retorna false o laço termina na hora com o
+// erro, como um erro permanente. O padrão repete qualquer erro não nil, o que
+// permite centralizar a política, por exemplo repetir só timeouts.
+func WithRetryIf(fn func(err error) bool) Option {
+	return func(b *Backoff) {
+		b.retryIf = fn
+	}
+}
+
+// IsPermanent informa se err, ou algum erro encadeado, é permanente.
+func IsPermanent(err error) bool {
+	return errors.Is(err, ErrPermanent)
+}
+
+// Retry chama fn até que ela retorne sem erro, dormindo b.Next() entre as
+// tentativas. Retorna o último resultado e erro quando o contexto termina,
+// os limites verificados por NextOK são atingidos ou fn retorna um erro
+// permanente.
+// Em caso de sucesso o backoff é reiniciado.
+func Retry[T any](ctx context.Context, b *Backoff, fn func() (T, error)) (T, error) {
+	var v T
+	err := retry(ctx, b, func() error {
+		var err error
+		v, err = fn()
+		return err
+	}, nil)
+	return v, err
+}
+
+// Do chama fn até que ela retorne nil, nas mesmas condições de Retry. Se
+// notify não for nil, é chamada antes de cada espera com o erro e o
+// intervalo seguinte.
+func Do(ctx context.Context, b *Backoff, fn func() error, notify func(err error, next time.Duration)) error {
+	if notify == nil {
+		return retry(ctx, b, fn, nil)
+	}
+	return retry(ctx, b, fn, func(err error, _ int, next time.Duration) {
+		notify(err, next)
+	})
+}
+
+// RetryNotify funciona como Do, mas notify também recebe o número da
+// tentativa que falhou (1, 2, ...). notify é chamada uma vez por falha seguida
+// de espera, nunca após o sucesso.
+func RetryNotify(ctx context.Context, b *Backoff, op func() error, notify func(err error, attempt int, next time.Duration)) error {
+	return retry(ctx, b, op, notify)
+}
+
+// RetryCollect funciona como Do sem notify, mas retorna todos os erros das
+// tentativas que falharam, na ordem em que ocorreram, em vez de só o último.
+// Retorna nil se op acabar tendo sucesso. Útil para diagnosticar operações
+// instáveis cujas falhas variam de uma tentativa para outra; errors.Join
+// combina o resultado em um único erro.
+func RetryCollect(ctx context.Context, b *Backoff, op func() error) []error {
+	var errs []error
+	err := retry(ctx, b, func() error {
+		err := op()
+		if err != nil {
+			errs = append(errs, err)
+		}
+		return err
+	}, nil)
+	if err == nil {
+		return nil
+	}
+	return errs
+}
+
+// DoOnce chama op até que ela retorne nil, nas mesmas condições de Do, mas
+// coordena chamadas concorrentes no mesmo Backoff: só a primeira executa o
+// laço de retry e as que chegam enquanto ele está em andamento esperam e
+// recebem o mesmo resultado, em vez de repetirem op cada uma. Evita
+// multiplicar a carga quando várias goroutines dependem da mesma
+// inicialização. Uma chamada feita depois que o laço termina inicia outro.
+// O contexto do laço é o da primeira chamada; as demais só usam o seu para
+// desistir de esperar, retornando ctx.Err().
+func (b *Backoff) DoOnce(ctx context.Context, op func() error) error {
+	f := &flight{done: make(chan struct{}), err: errFlightPanic}
+	for {
+		if b.flight.CompareAndSwap(nil, f) {
+			defer func() {
+				b.flight.Store(nil)
+				close(f.done)
+			}()
+			f.err = retry(ctx, b, op, nil)
+			return f.err
+		}
+		if cur := b.flight.Load(); cur != nil {
+			select {
+			case <-cur.done:
+				return cur.err
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+	}
+}
+
+// flight é um laço de retry de DoOnce em andamento.
+type flight struct {
+	done chan struct{} // fechado quando o laço termina
+	err  error         // resultado do laço, válido depois de done
+}
+
+// errFlightPanic é entregue a quem espera DoOnce quando op entra em pânico.
+var errFlightPanic = errors.New("backoff: DoOnce operation panicked")
+
+// ReconnectLoop tenta connect até que ela retorne nil, dormindo entre as
+// tentativas, no padrão "continuar tentando (re)conectar". Depois de uma
+// conexão bem-sucedida o backoff é reiniciado, então uma queda posterior
+// recomeça do intervalo inicial. Erros permanentes e os limites verificados
+// por NextOK encerram o laço com o último erro; o cancelamento de ctx retorna
+// um erro que envolve ctx.Err() e o último erro de connect.
+func ReconnectLoop(ctx context.Context, b *Backoff, connect func(ctx context.Context) error) error {
+	err := retry(ctx, b, func() error { return connect(ctx) }, nil)
+	if err != nil && ctx.Err() != nil {
+		return fmt.Errorf("backoff: reconnect: %w (last error: %w)", ctx.Err(), err)
+	}
+	return err
+}
+
+// Erros de DoUntil, que indicam qual limite foi atingido. O erro retornado
+// envolve também o último erro de op.
+var (
+	ErrMaxAttempts  = errors.New("backoff: max attempts reached")
+	ErrMaxTotalTime = errors.New("backoff: max total time reached")
+)
+
+// DoUntil chama op até que ela retorne nil, com no máximo maxAttempts
+// chamadas e sem passar de maxTotal de tempo total, medido pelo relógio do
+// Backoff desde a primeira chamada; zero desliga o limite correspondente. Para
+// no primeiro limite atingido: retorna um erro que envolve ErrMaxAttempts ou
+// ErrMaxTotalTime, ou ctx.Err() se o contexto terminar, junto com o último
+// erro de op. Uma espera que ultrapassaria maxTotal não é feita. Erros
+// permanentes e os limites verificados por NextOK valem como em Retry.
+func DoUntil(ctx context.Context, b *Backoff, maxAttempts int, maxTotal time.Duration, op func() error) error {
+	var s sleeper
+	defer s.stop()
+
+	clock := b.getClock()
+	start := clock.Now()
+	for attempt := 1; ; attempt++ {
+		err := op()
+		if err == nil {
+			b.Reset()
+			return nil
+		}
+		if IsPermanent(err) || (b.retryIf != nil && !b.retryIf(err)) {
+			return err
+		}
+		if maxAttempts > 0 && attempt >= maxAttempts {
+			return fmt.Errorf("%w (last error: %w)", ErrMaxAttempts, err)
+		}
+
+		d, ok := b.NextOK()
+		if !ok {
+			return err
+		}
+		if maxTotal > 0 && clock.Now().Sub(start) > maxTotal-d {
+			return fmt.Errorf("%w (last error: %w)", ErrMaxTotalTime, err)
+		}
+		if s.sleep(ctx, clock, d) != nil {
+			return fmt.Errorf("backoff: %w (last error: %w)", ctx.Err(), err)
+		}
+	}
+}
+
+// WaitLoop chama op até que ela retorne nil, nas mesmas condições de Retry.
+// Um único timer é criado e reiniciado a cada espera, reduzindo alocações em
+// laços de alta frequência; Retry, Do e RetryNotify usam o mesmo laço.
+func (b *Backoff) WaitLoop(ctx context.Context, op func() error) error {
+	return retry(ctx, b, op, nil)
+}
+
+// retry implementa o laço comum a Retry, Do, RetryNotify e WaitLoop.
+func retry(ctx context.Context, b *Backoff, fn func() error, notify func(error, int, time.Duration)) error {
+	var s sleeper
+	defer s.stop()
+
+	clock := b.getClock()
+	for attempt := 1; ; attempt++ {
+		err := fn()
+		if err == nil {
+			b.Reset()
+			return nil
+		}
+		if IsPermanent(err) || (b.retryIf != nil && !b.retryIf(err)) {
+			return err
+		}
+
+		d, ok := b.NextOK()
+		if !ok {
+			return err
+		}
+		if notify != nil {
+			notify(err, attempt, d)
+		}
+		if s.sleep(ctx, clock, d) != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,756 @@
+package backoff
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+	"testing"
+	"testing/synctest"
+	"time"
+)
+
+func TestRetry(t *testing.T) {
+	errTemporary := errors.New("temporary")
+
+	tests := []struct {
+		name      string
+		opts      []Option
+		failures  int
+		failWith  error
+		wantValue int
+		wantErr   error
+		wantCalls int
+	}{
+		{
+			name:      "success on third try",
+			failures:  2,
+			failWith:  errTemporary,
+			wantValue: 3,
+			wantErr:   nil,
+			wantCalls: 3,
+		},
+		{
+			name:      "permanent error short-circuits",
+			failures:  5,
+			failWith:  fmt.Errorf("bad request: %w", ErrPermanent),
+			wantValue: 1,
+			wantErr:   ErrPermanent,
+			wantCalls: 1,
+		},
+		{
+			name:      "Permanent wrapper short-circuits",
+			failures:  5,
+			failWith:  Permanent(errors.New("bad request")),
+			wantValue: 1,
+			wantErr:   ErrPermanent,
+			wantCalls: 1,
+		},
+		{
+			name:      "max attempts exhausted",
+			opts:      []Option{WithMaxAttempts(2)},
+			failures:  5,
+			failWith:  errTemporary,
+			wantValue: 3,
+			wantErr:   errTemporary,
+			wantCalls: 3,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := append([]Option{WithJitter(false)}, tt.opts...)
+			b := New(1*time.Millisecond, 2.0, 10*time.Millisecond, opts...)
+
+			calls := 0
+			v, err := Retry(context.Background(), b, func() (int, error) {
+				calls++
+				if calls <= tt.failures {
+					return calls, tt.failWith
+				}
+				return calls, nil
+			})
+
+			if !errors.Is(err, tt.wantErr) || (tt.wantErr == nil && err != nil) {
+				t.Errorf("Retry() error = %v, want %v", err, tt.wantErr)
+			}
+			if v != tt.wantValue {
+				t.Errorf("Retry() value = %d, want %d", v, tt.wantValue)
+			}
+			if calls != tt.wantCalls {
+				t.Errorf("Retry() called fn %d times, want %d", calls, tt.wantCalls)
+			}
+		})
+	}
+}
+
+func TestPermanent(t *testing.T) {
+	errBase := errors.New("bad request")
+
+	if Permanent(nil) != nil {
+		t.Errorf("Permanent(nil) should be nil")
+	}
+
+	tests := []struct {
+		name          string
+		err           error
+		wantPermanent bool
+	}{
+		{"nil", nil, false},
+		{"plain error", errBase, false},
+		{"wrapped with Permanent", Permanent(errBase), true},
+		{"Permanent wrapped again", fmt.Errorf("op: %w", Permanent(errBase)), true},
+		{"sentinel", fmt.Errorf("op: %w", ErrPermanent), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsPermanent(tt.err); got != tt.wantPermanent {
+				t.Errorf("IsPermanent() = %v, want %v", got, tt.wantPermanent)
+			}
+		})
+	}
+
+	err := fmt.Errorf("op: %w", Permanent(errBase))
+	if !errors.Is(err, errBase) {
+		t.Errorf("errors.Is() should find the original error through Permanent")
+	}
+	var perr *PermanentError
+	if !errors.As(err, &perr) {
+		t.Fatalf("errors.As() should find *PermanentError")
+	}
+	if perr.Err != errBase {
+		t.Errorf("PermanentError.Err = %v, want %v", perr.Err, errBase)
+	}
+	if err.Error() != "op: bad request" {
+		t.Errorf("Error() = %q, want %q", err.Error(), "op: bad request")
+	}
+}
+
+func TestRetry_ResetsOnSuccess(t *testing.T) {
+	b := New(1*time.Millisecond, 2.0, 10*time.Millisecond, WithJitter(false))
+
+	calls := 0
+	_, err := Retry(context.Background(), b, func() (int, error) {
+		calls++
+		if calls < 3 {
+			return 0, errors.New("temporary")
+		}
+		return 0, nil
+	})
+	if err != nil {
+		t.Fatalf("Retry() error = %v", err)
+	}
+	if got := b.Attempt(); got != 0 {
+		t.Errorf("Attempt() after successful Retry() = %d, want 0", got)
+	}
+}
+
+func TestRetry_ContextCanceled(t *testing.T) {
+	b := New(1*time.Hour, 2.0, 2*time.Hour, WithJitter(false))
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	errTemporary := errors.New("temporary")
+	calls := 0
+	start := time.Now()
+	_, err := Retry(ctx, b, func() (string, error) {
+		calls++
+		return "", errTemporary
+	})
+
+	if !errors.Is(err, errTemporary) {
+		t.Errorf("Retry() error = %v, want %v", err, errTemporary)
+	}
+	if calls != 1 {
+		t.Errorf("Retry() called fn %d times, want 1", calls)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Retry() did not return promptly on cancellation: %v", elapsed)
+	}
+}
+
+func TestDo(t *testing.T) {
+	errTemporary := errors.New("temporary")
+
+	tests := []struct {
+		name        string
+		maxAttempts int
+		failures    int
+		timeout     time.Duration
+		initial     time.Duration
+		wantErr     error
+		wantCalls   int
+		wantNotify  []time.Duration
+	}{
+		{
+			name:       "success after retries",
+			failures:   2,
+			initial:    1 * time.Millisecond,
+			wantErr:    nil,
+			wantCalls:  3,
+			wantNotify: []time.Duration{1 * time.Millisecond, 2 * time.Millisecond},
+		},
+		{
+			name:        "attempts exhausted",
+			maxAttempts: 2,
+			failures:    10,
+			initial:     1 * time.Millisecond,
+			wantErr:     errTemporary,
+			wantCalls:   3,
+			wantNotify:  []time.Duration{1 * time.Millisecond, 2 * time.Millisecond},
+		},
+		{
+			name:       "context canceled during sleep",
+			failures:   10,
+			timeout:    10 * time.Millisecond,
+			initial:    1 * time.Hour,
+			wantErr:    errTemporary,
+			wantCalls:  1,
+			wantNotify: []time.Duration{1 * time.Hour},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			if tt.timeout > 0 {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithTimeout(ctx, tt.timeout)
+				defer cancel()
+			}
+
+			b := New(tt.initial, 2.0, 10*tt.initial, WithJitter(false), WithMaxAttempts(tt.maxAttempts))
+
+			calls := 0
+			var notified []time.Duration
+			err := Do(ctx, b, func() error {
+				calls++
+				if calls <= tt.failures {
+					return errTemporary
+				}
+				return nil
+			}, func(err error, next time.Duration) {
+				if !errors.Is(err, errTemporary) {
+					t.Errorf("notify() error = %v, want %v", err, errTemporary)
+				}
+				notified = append(notified, next)
+			})
+
+			if err != tt.wantErr {
+				t.Errorf("Do() error = %v, want %v", err, tt.wantErr)
+			}
+			if calls != tt.wantCalls {
+				t.Errorf("Do() called fn %d times, want %d", calls, tt.wantCalls)
+			}
+			if len(notified) != len(tt.wantNotify) {
+				t.Fatalf("notify() called %d times, want %d", len(notified), len(tt.wantNotify))
+			}
+			for i, want := range tt.wantNotify {
+				if notified[i] != want {
+					t.Errorf("notify() call %d next = %v, want %v", i+1, notified[i], want)
+				}
+			}
+		})
+	}
+}
+
+func TestDo_NilNotify(t *testing.T) {
+	b := New(1*time.Millisecond, 2.0, 10*time.Millisecond, WithJitter(false))
+
+	calls := 0
+	err := Do(context.Background(), b, func() error {
+		calls++
+		if calls < 2 {
+			return errors.New("temporary")
+		}
+		return nil
+	}, nil)
+	if err != nil || calls != 2 {
+		t.Errorf("Do() = %v after %d calls, want nil after 2", err, calls)
+	}
+}
+
+func TestRetryNotify(t *testing.T) {
+	type failure struct {
+		attempt int
+		next    time.Duration
+	}
+
+	b := New(1*time.Millisecond, 2.0, 10*time.Millisecond, WithJitter(false))
+
+	calls := 0
+	var got []failure
+	err := RetryNotify(context.Background(), b, func() error {
+		calls++
+		if calls <= 3 {
+			return errors.New("temporary")
+		}
+		return nil
+	}, func(err error, attempt int, next time.Duration) {
+		if err == nil {
+			t.Errorf("notify() called with nil error on attempt %d", attempt)
+		}
+		got = append(got, failure{attempt, next})
+	})
+	if err != nil {
+		t.Fatalf("RetryNotify() error = %v, want nil", err)
+	}
+
+	// once per failed attempt, never after the final success
+	want := []failure{{1, 1 * time.Millisecond}, {2, 2 * time.Millisecond}, {3, 4 * time.Millisecond}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("notify() calls = %v, want %v", got, want)
+	}
+}
+
+func TestWaitLoop(t *testing.T) {
+	errTemporary := errors.New("temporary")
+
+	tests := []struct {
+		name      string
+		failures  int // op fails this many times before succeeding
+		cancelAt  int // cancel during this sleep (0 = never)
+		wantErr   error
+		wantCalls int
+	}{
+		{"succeeds after retries", 3, 0, nil, 4},
+		{"canceled mid-run", 5, 2, errTemporary, 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clk := newFakeClock()
+			b := New(1*time.Second, 2.0, 1*time.Minute, WithJitter(false), WithClock(clk))
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			calls := 0
+			errc := make(chan error, 1)
+			go func() {
+				errc <- b.WaitLoop(ctx, func() error {
+					calls++
+					if calls <= tt.failures {
+						return errTemporary
+					}
+					return nil
+				})
+			}()
+
+			for i := 1; i <= tt.failures; i++ {
+				<-clk.added
+				if i == tt.cancelAt {
+					cancel()
+					break
+				}
+				clk.Advance(time.Duration(1<<(i-1)) * time.Second)
+			}
+
+			if err := <-errc; !errors.Is(err, tt.wantErr) {
+				t.Fatalf("WaitLoop() error = %v, want %v", err, tt.wantErr)
+			}
+			if calls != tt.wantCalls {
+				t.Errorf("WaitLoop() called op %d times, want %d", calls, tt.wantCalls)
+			}
+			clk.mu.Lock()
+			defer clk.mu.Unlock()
+			if n := len(clk.timers); n != 1 {
+				t.Errorf("WaitLoop() created %d timers, want 1", n)
+			}
+			for _, timer := range clk.timers {
+				if timer.active {
+					t.Errorf("WaitLoop() left its timer running")
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkWaitLoop(b *testing.B) {
+	const attempts = 100
+	errTemporary := errors.New("temporary")
+
+	benchmarks := []struct {
+		name string
+		loop func(ctx context.Context, bo *Backoff, op func() error)
+	}{
+		// a new timer per sleep
+		{"Wait", func(ctx context.Context, bo *Backoff, op func() error) {
+			for op() != nil {
+				_ = bo.Wait(ctx)
+			}
+		}},
+		// one timer reset on each sleep
+		{"WaitLoop", func(ctx context.Context, bo *Backoff, op func() error) {
+			_ = bo.WaitLoop(ctx, op)
+		}},
+	}
+
+	for _, bm := range benchmarks {
+		b.Run(bm.name, func(b *testing.B) {
+			ctx := context.Background()
+			bo := Constant(1*time.Nanosecond, WithJitter(false))
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				calls := 0
+				bm.loop(ctx, bo, func() error {
+					if calls++; calls < attempts {
+						return errTemporary
+					}
+					return nil
+				})
+			}
+		})
+	}
+}
+
+func TestReconnectLoop(t *testing.T) {
+	errRefused := errors.New("connection refused")
+
+	t.Run("fails twice then connects", func(t *testing.T) {
+		clk := newFakeClock()
+		b := New(1*time.Second, 2.0, 1*time.Minute, WithJitter(false), WithClock(clk))
+
+		calls := 0
+		errc := make(chan error, 1)
+		go func() {
+			errc <- ReconnectLoop(context.Background(), b, func(ctx context.Context) error {
+				if calls++; calls <= 2 {
+					return errRefused
+				}
+				return nil
+			})
+		}()
+
+		<-clk.added
+		clk.Advance(1 * time.Second)
+		<-clk.added
+		clk.Advance(2 * time.Second)
+
+		if err := <-errc; err != nil {
+			t.Fatalf("ReconnectLoop() error = %v, want nil", err)
+		}
+		if calls != 3 {
+			t.Errorf("ReconnectLoop() called connect %d times, want 3", calls)
+		}
+		// a later disconnect starts cheap
+		if got := b.Next(); got != 1*time.Second {
+			t.Errorf("Next() after reconnecting = %v, want %v", got, 1*time.Second)
+		}
+	})
+
+	t.Run("context canceled", func(t *testing.T) {
+		clk := newFakeClock()
+		b := New(1*time.Second, 2.0, 1*time.Minute, WithJitter(false), WithClock(clk))
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		var connectCtx context.Context
+		errc := make(chan error, 1)
+		go func() {
+			errc <- ReconnectLoop(ctx, b, func(ctx context.Context) error {
+				connectCtx = ctx
+				return errRefused
+			})
+		}()
+
+		<-clk.added
+		cancel()
+
+		err := <-errc
+		if !errors.Is(err, context.Canceled) || !errors.Is(err, errRefused) {
+			t.Errorf("ReconnectLoop() error = %v, want both %v and %v", err, context.Canceled, errRefused)
+		}
+		if connectCtx != ctx {
+			t.Errorf("connect received a different context")
+		}
+	})
+
+	t.Run("permanent error", func(t *testing.T) {
+		b := New(1*time.Second, 2.0, 1*time.Minute)
+		errAuth := Permanent(errors.New("authentication failed"))
+		if err := ReconnectLoop(context.Background(), b, func(context.Context) error { return errAuth }); err != errAuth {
+			t.Errorf("ReconnectLoop() error = %v, want %v", err, errAuth)
+		}
+	})
+}
+
+// timeoutError is a retryable error for TestWithRetryIf.
+type timeoutError struct{}
+
+func (timeoutError) Error() string { return "timeout" }
+
+func TestWithRetryIf(t *testing.T) {
+	errNotFound := errors.New("not found")
+	onlyTimeouts := func(err error) bool {
+		var te timeoutError
+		return errors.As(err, &te)
+	}
+
+	tests := []struct {
+		name      string
+		retryIf   func(error) bool
+		errs      []error // returned by successive calls, then nil
+		wantErr   error
+		wantCalls int
+	}{
+		{"default retries all", nil, []error{errNotFound, timeoutError{}}, nil, 3},
+		{"classified as retryable", onlyTimeouts, []error{timeoutError{}, fmt.Errorf("dial: %w", timeoutError{})}, nil, 3},
+		{"refused stops immediately", onlyTimeouts, []error{timeoutError{}, errNotFound, timeoutError{}}, errNotFound, 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := New(1*time.Millisecond, 1.0, 1*time.Millisecond, WithJitter(false), WithRetryIf(tt.retryIf))
+
+			calls := 0
+			err := Do(context.Background(), b, func() error {
+				calls++
+				if calls <= len(tt.errs) {
+					return tt.errs[calls-1]
+				}
+				return nil
+			}, nil)
+
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Do() error = %v, want %v", err, tt.wantErr)
+			}
+			if calls != tt.wantCalls {
+				t.Errorf("Do() called fn %d times, want %d", calls, tt.wantCalls)
+			}
+		})
+	}
+}
+
+func TestDoUntil(t *testing.T) {
+	errTemporary := errors.New("temporary")
+
+	tests := []struct {
+		name        string
+		maxAttempts int
+		maxTotal    time.Duration
+		succeedAt   int  // call that succeeds (0 = never)
+		cancelAt    int  // cancel during this sleep (0 = never)
+		opAdvance   bool // each op call takes 1s on the clock
+		wantErr     error
+		wantCalls   int
+	}{
+		{"succeeds", 5, 30 * time.Second, 3, 0, false, nil, 3},
+		{"max attempts", 3, 30 * time.Second, 0, 0, false, ErrMaxAttempts, 3},
+		// sleeps of 1s, 2s, 4s, 8s plus 1s per call: the 5th sleep would pass 30s
+		{"max total time", 10, 30 * time.Second, 0, 0, true, ErrMaxTotalTime, 5},
+		{"context canceled", 10, 30 * time.Second, 0, 2, false, context.Canceled, 2},
+		{"no limits", 0, 0, 6, 0, false, nil, 6},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clk := newFakeClock()
+			b := New(1*time.Second, 2.0, 1*time.Minute, WithJitter(false), WithClock(clk))
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			calls := 0
+			errc := make(chan error, 1)
+			go func() {
+				errc <- DoUntil(ctx, b, tt.maxAttempts, tt.maxTotal, func() error {
+					calls++
+					if tt.opAdvance {
+						clk.Advance(1 * time.Second)
+					}
+					if calls == tt.succeedAt {
+						return nil
+					}
+					return errTemporary
+				})
+			}()
+
+			// fire every sleep until DoUntil returns
+			var err error
+		loop:
+			for sleep := 1; ; sleep++ {
+				select {
+				case err = <-errc:
+					break loop
+				case <-clk.added:
+					if sleep == tt.cancelAt {
+						cancel()
+						continue
+					}
+					clk.Advance(time.Duration(1<<(sleep-1)) * time.Second)
+				}
+			}
+
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("DoUntil() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil && !errors.Is(err, errTemporary) {
+				t.Errorf("DoUntil() error = %v, want it to wrap the last error %v", err, errTemporary)
+			}
+			if calls != tt.wantCalls {
+				t.Errorf("DoUntil() called op %d times, want %d", calls, tt.wantCalls)
+			}
+		})
+	}
+}
+
+func TestRetryCollect(t *testing.T) {
+	errTimeout := errors.New("timeout")
+	errRefused := errors.New("connection refused")
+	errReset := errors.New("connection reset")
+
+	tests := []struct {
+		name string
+		opts []Option
+		errs []error // returned by successive calls, then nil
+		want []error
+	}{
+		{"eventual success", nil, []error{errTimeout, errRefused}, nil},
+		{"first call succeeds", nil, nil, nil},
+		{"attempts exhausted", []Option{WithMaxAttempts(2)},
+			[]error{errTimeout, errRefused, errReset, errTimeout}, []error{errTimeout, errRefused, errReset}},
+		{"permanent error", nil,
+			[]error{errTimeout, Permanent(errReset)}, []error{errTimeout, Permanent(errReset)}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := New(1*time.Millisecond, 1.0, 1*time.Millisecond, append(tt.opts, WithJitter(false))...)
+
+			calls := 0
+			got := RetryCollect(context.Background(), b, func() error {
+				calls++
+				if calls <= len(tt.errs) {
+					return tt.errs[calls-1]
+				}
+				return nil
+			})
+			if len(got) != len(tt.want) {
+				t.Fatalf("RetryCollect() = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i].Error() != tt.want[i].Error() {
+					t.Errorf("RetryCollect()[%d] = %v, want %v", i, got[i], tt.want[i])
+				}
+			}
+			if joined := errors.Join(got...); tt.want != nil && !errors.Is(joined, tt.want[0]) {
+				t.Errorf("errors.Join(RetryCollect()) = %v, want it to match %v", joined, tt.want[0])
+			}
+		})
+	}
+}
+
+func TestBackoff_DoOnce(t *testing.T) {
+	errUnavailable := errors.New("unavailable")
+
+	tests := []struct {
+		name      string
+		failures  int // op errors before succeeding
+		opts      []Option
+		wantErr   error
+		wantCalls int
+	}{
+		{"first call succeeds", 0, nil, nil, 1},
+		{"succeeds after retries", 2, nil, nil, 3},
+		{"attempts exhausted", 10, []Option{WithMaxAttempts(3)}, errUnavailable, 4},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			synctest.Test(t, func(t *testing.T) {
+				b := New(1*time.Second, 2.0, 10*time.Second, tt.opts...)
+				gate := make(chan struct{})
+
+				var mu sync.Mutex
+				calls := 0
+				op := func() error {
+					<-gate
+					mu.Lock()
+					defer mu.Unlock()
+					calls++
+					if calls <= tt.failures {
+						return errUnavailable
+					}
+					return nil
+				}
+
+				const callers = 20
+				errs := make(chan error, callers)
+				for range callers {
+					go func() { errs <- b.DoOnce(context.Background(), op) }()
+				}
+				// every caller is either running op or waiting for it
+				synctest.Wait()
+				close(gate)
+
+				for range callers {
+					if err := <-errs; !errors.Is(err, tt.wantErr) {
+						t.Errorf("DoOnce() = %v, want %v", err, tt.wantErr)
+					}
+				}
+				if calls != tt.wantCalls {
+					t.Errorf("op ran %d times across %d callers, want %d", calls, callers, tt.wantCalls)
+				}
+
+				// a later call starts a fresh effort
+				calls = 0
+				if err := b.DoOnce(context.Background(), op); !errors.Is(err, tt.wantErr) {
+					t.Errorf("later DoOnce() = %v, want %v", err, tt.wantErr)
+				}
+				if calls == 0 {
+					t.Error("later DoOnce() did not run op")
+				}
+			})
+		})
+	}
+
+	t.Run("waiter gives up on its context", func(t *testing.T) {
+		synctest.Test(t, func(t *testing.T) {
+			b := New(1*time.Second, 2.0, 10*time.Second)
+			gate := make(chan struct{})
+			leader := make(chan error)
+			go func() {
+				leader <- b.DoOnce(context.Background(), func() error {
+					<-gate
+					return nil
+				})
+			}()
+			synctest.Wait()
+
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+			if err := b.DoOnce(ctx, func() error { return nil }); !errors.Is(err, context.Canceled) {
+				t.Errorf("waiting DoOnce() = %v, want %v", err, context.Canceled)
+			}
+
+			close(gate)
+			if err := <-leader; err != nil {
+				t.Errorf("leading DoOnce() = %v, want nil", err)
+			}
+		})
+	})
+
+	t.Run("panic releases waiters", func(t *testing.T) {
+		synctest.Test(t, func(t *testing.T) {
+			b := New(1*time.Second, 2.0, 10*time.Second)
+			gate := make(chan struct{})
+			go func() {
+				defer func() { recover() }()
+				b.DoOnce(context.Background(), func() error {
+					<-gate
+					panic("boom")
+				})
+			}()
+			synctest.Wait()
+
+			waiter := make(chan error)
+			go func() { waiter <- b.DoOnce(context.Background(), func() error { return nil }) }()
+			synctest.Wait()
+			close(gate)
+			if err := <-waiter; err == nil {
+				t.Error("DoOnce() waiting on a panicking op = nil, want an error")
+			}
+		})
+	})
+}
@@ -0,0 +1,127 @@
+package backoff
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// State é o estado de progressão de um Backoff, para persistência entre
+// reinícios. A configuração (initial, factor, max e opções) não faz parte do
+// State: ela deve ser reconstruída pelo código antes de Restore.
+type State struct {
+	Current     time.Duration `json:"current"`
+	Attempt     int           `json:"attempt"`
+	Initialized bool          `json:"initialized"`
+	Start       time.Time     `json:"start,omitzero"` // zero antes da primeira chamada a Next
+}
+
+// Snapshot retorna o estado atual.
+func (b *Backoff) Snapshot() State {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	cur, ok := decodeCur(b.cur.Load())
+	s := State{
+		Current:     cur,
+		Attempt:     int(b.attempt.Load()),
+		Initialized: ok,
+	}
+	if start := b.start.Load(); start != nil {
+		// descarta a leitura monotônica, que não sobrevive a reinícios
+		s.Start = start.Round(0)
+	}
+	return s
+}
+
+// Restore carrega um estado obtido por Snapshot. O tempo decorrido de
+// WithMaxElapsedTime continua a contar a partir de Start, ou recomeça no
+// próximo Next se Start for zero; a soma de WithMaxTotalDelay recomeça.
+func (b *Backoff) Restore(s State) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.cur.Store(0)
+	if s.Initialized {
+		b.cur.Store(encodeCur(s.Current))
+	}
+	b.last = s.Current
+	b.attempt.Store(int64(s.Attempt))
+	b.maxed.Store(false)
+	b.stagger.Store(0)
+	b.primed = false
+	b.start.Store(nil)
+	if !s.Start.IsZero() {
+		b.start.Store(&s.Start)
+	}
+	b.total = 0
+}
+
+// Formato binário de State: um byte de versão seguido dos campos da versão.
+// Versão 1: byte de flags (stateInitialized, stateStarted), Current e Attempt
+// como varints e, com stateStarted, Start em nanossegundos Unix como varint.
+const stateVersion = 1
+
+// Flags do formato binário de State.
+const (
+	stateInitialized = 1 << iota
+	stateStarted
+)
+
+// MarshalBinary implementa encoding.BinaryMarshaler com um formato compacto e
+// versionado. Start é gravado com precisão de nanossegundos, sem fuso.
+func (s State) MarshalBinary() ([]byte, error) {
+	var flags byte
+	if s.Initialized {
+		flags |= stateInitialized
+	}
+	if !s.Start.IsZero() {
+		flags |= stateStarted
+	}
+
+	buf := make([]byte, 0, 2+3*binary.MaxVarintLen64)
+	buf = append(buf, stateVersion, flags)
+	buf = binary.AppendVarint(buf, int64(s.Current))
+	buf = binary.AppendVarint(buf, int64(s.Attempt))
+	if flags&stateStarted != 0 {
+		buf = binary.AppendVarint(buf, s.Start.UnixNano())
+	}
+	return buf, nil
+}
+
+// UnmarshalBinary implementa encoding.BinaryUnmarshaler. Retorna erro para
+// versões desconhecidas ou dados truncados.
+func (s *State) UnmarshalBinary(data []byte) error {
+	if len(data) == 0 {
+		return errors.New("backoff: empty state")
+	}
+	if data[0] != stateVersion {
+		return fmt.Errorf("backoff: unsupported state version %d", data[0])
+	}
+	if len(data) < 2 {
+		return errors.New("backoff: truncated state")
+	}
+	flags, rest := data[1], data[2:]
+
+	var fields [3]int64
+	n := 2
+	if flags&stateStarted != 0 {
+		n = 3
+	}
+	for i := range n {
+		v, size := binary.Varint(rest)
+		if size <= 0 {
+			return errors.New("backoff: truncated state")
+		}
+		fields[i], rest = v, rest[size:]
+	}
+
+	*s = State{
+		Current:     time.Duration(fields[0]),
+		Attempt:     int(fields[1]),
+		Initialized: flags&stateInitialized != 0,
+	}
+	if flags&stateStarted != 0 {
+		s.Start = time.Unix(0, fields[2]).UTC()
+	}
+	return nil
+}
@@ -0,0 +1,135 @@
+package backoff
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestBackoff_SnapshotRestore(t *testing.T) {
+	clk := newFakeClock()
+	b := New(100*time.Millisecond, 2.0, 5*time.Second, WithJitter(false), WithClock(clk))
+	start := clk.Now()
+	b.Next()
+	clk.Advance(1 * time.Second)
+	b.Next()
+	b.Next()
+
+	s := b.Snapshot()
+	want := State{Current: 400 * time.Millisecond, Attempt: 3, Initialized: true, Start: start}
+	if s != want {
+		t.Fatalf("Snapshot() = %+v, want %+v", s, want)
+	}
+
+	data, err := json.Marshal(s)
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+	var decoded State
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+	if decoded != s {
+		t.Errorf("JSON round trip = %+v, want %+v", decoded, s)
+	}
+
+	// A freshly constructed backoff resumes where the original stopped
+	r := New(100*time.Millisecond, 2.0, 5*time.Second, WithJitter(false))
+	r.Restore(decoded)
+	if got := r.Attempt(); got != 3 {
+		t.Errorf("Attempt() after Restore() = %d, want 3", got)
+	}
+	if got, want := r.Next(), b.Next(); got != want {
+		t.Errorf("Next() after Restore() = %v, want %v", got, want)
+	}
+
+	// elapsed time keeps counting from the restored start
+	r2 := New(100*time.Millisecond, 2.0, 5*time.Second, WithClock(clk))
+	r2.Restore(decoded)
+	if got := r2.Elapsed(); got != 1*time.Second {
+		t.Errorf("Elapsed() after Restore() = %v, want %v", got, 1*time.Second)
+	}
+}
+
+func TestState_BinaryRoundTrip(t *testing.T) {
+	start := time.Date(2024, time.January, 1, 12, 0, 0, 123, time.UTC)
+
+	tests := []struct {
+		name  string
+		state State
+	}{
+		{"uninitialized", State{}},
+		{"initialized", State{Current: 400 * time.Millisecond, Attempt: 3, Initialized: true}},
+		{"with start", State{Current: 30 * time.Second, Attempt: 12, Initialized: true, Start: start}},
+		{"large values", State{Current: 1<<63 - 1, Attempt: 1 << 40, Initialized: true, Start: start}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := tt.state.MarshalBinary()
+			if err != nil {
+				t.Fatalf("MarshalBinary() error = %v", err)
+			}
+			if data[0] != stateVersion {
+				t.Errorf("MarshalBinary() version byte = %d, want %d", data[0], stateVersion)
+			}
+
+			var got State
+			if err := got.UnmarshalBinary(data); err != nil {
+				t.Fatalf("UnmarshalBinary() error = %v", err)
+			}
+			if got != tt.state {
+				t.Errorf("binary round trip = %+v, want %+v", got, tt.state)
+			}
+		})
+	}
+
+	// an uninitialized backoff restores as uninitialized
+	var s State
+	data, _ := New(100*time.Millisecond, 2.0, 5*time.Second).Snapshot().MarshalBinary()
+	if err := s.UnmarshalBinary(data); err != nil || s.Initialized || !s.Start.IsZero() {
+		t.Errorf("UnmarshalBinary() of fresh backoff = %+v, %v, want zero state", s, err)
+	}
+
+	// much smaller than JSON
+	full := State{Current: 30 * time.Second, Attempt: 12, Initialized: true, Start: start}
+	bin, _ := full.MarshalBinary()
+	js, _ := json.Marshal(full)
+	if len(bin) >= len(js)/3 {
+		t.Errorf("binary size = %d bytes, JSON = %d bytes", len(bin), len(js))
+	}
+}
+
+func TestState_UnmarshalBinaryErrors(t *testing.T) {
+	valid, _ := State{Current: 1 * time.Second, Attempt: 2, Initialized: true, Start: time.Unix(100, 0)}.MarshalBinary()
+
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{"empty", nil},
+		{"unknown version", append([]byte{2}, valid[1:]...)},
+		{"version only", valid[:1]},
+		{"truncated fields", valid[:3]},
+		{"missing start", valid[:len(valid)-1]},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var s State
+			if err := s.UnmarshalBinary(tt.data); err == nil {
+				t.Errorf("UnmarshalBinary(%v) error = nil, want error", tt.data)
+			}
+		})
+	}
+}
+
+func TestBackoff_RestoreUninitialized(t *testing.T) {
+	b := New(100*time.Millisecond, 2.0, 5*time.Second, WithJitter(false))
+	b.Next()
+
+	b.Restore(New(100*time.Millisecond, 2.0, 5*time.Second).Snapshot())
+	if got := b.Next(); got != 100*time.Millisecond {
+		t.Errorf("Next() after restoring fresh state = %v, want %v", got, 100*time.Millisecond)
+	}
+}
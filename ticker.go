@@ -0,0 +1,56 @@
+package backoff
+
+import (
+	"context"
+	"time"
+)
+
+// Ticker entrega ticks em intervalos sucessivamente maiores, como um
+// time.Ticker cujo período segue o Backoff. Útil para polling que desacelera
+// com o tempo.
+type Ticker struct {
+	C <-chan time.Time // recebe o instante de cada tick
+
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// Ticker inicia um Ticker que espera cada intervalo retornado por NextOK e
+// então envia o instante em C. O envio espera o receptor, de modo que o
+// intervalo seguinte só começa depois que o tick é lido. C é fechado quando
+// ctx termina, os limites verificados por NextOK são atingidos ou Stop é
+// chamado.
+func (b *Backoff) Ticker(ctx context.Context) *Ticker {
+	ctx, cancel := context.WithCancel(ctx)
+	ch := make(chan time.Time)
+	t := &Ticker{C: ch, cancel: cancel, done: make(chan struct{})}
+
+	go func() {
+		defer close(t.done)
+		defer close(ch)
+
+		clock := b.getClock()
+		var s sleeper
+		defer s.stop()
+		for {
+			d, ok := b.NextOK()
+			if !ok || s.sleep(ctx, clock, d) != nil {
+				return
+			}
+			select {
+			case ch <- clock.Now():
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return t
+}
+
+// Stop encerra o Ticker e libera o timer e a goroutine internos. Ao retornar,
+// nenhum tick será mais enviado e C está fechado. Pode ser chamado mais de
+// uma vez.
+func (t *Ticker) Stop() {
+	t.cancel()
+	<-t.done
+}
@@ -0,0 +1,72 @@
+package backoff
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestBackoff_Ticker(t *testing.T) {
+	t.Run("intervals grow", func(t *testing.T) {
+		clk := newFakeClock()
+		b := New(1*time.Second, 2.0, 4*time.Second, WithJitter(false), WithClock(clk))
+		tk := b.Ticker(context.Background())
+		defer tk.Stop()
+
+		prev := clk.Now()
+		for i, want := range []time.Duration{1 * time.Second, 2 * time.Second, 4 * time.Second, 4 * time.Second} {
+			<-clk.added
+			clk.Advance(want)
+			tick := <-tk.C
+			if got := tick.Sub(prev); got != want {
+				t.Errorf("tick %d after %v, want %v", i+1, got, want)
+			}
+			prev = tick
+		}
+	})
+
+	t.Run("Stop halts emissions", func(t *testing.T) {
+		clk := newFakeClock()
+		b := New(1*time.Second, 2.0, 4*time.Second, WithJitter(false), WithClock(clk))
+		tk := b.Ticker(context.Background())
+
+		<-clk.added
+		clk.Advance(time.Second)
+		<-tk.C
+
+		<-clk.added
+		tk.Stop()
+		clk.Advance(time.Hour)
+		if tick, ok := <-tk.C; ok {
+			t.Errorf("received tick %v after Stop(), want closed channel", tick)
+		}
+		// a second Stop is harmless
+		tk.Stop()
+	})
+
+	t.Run("closes when the context ends", func(t *testing.T) {
+		b := New(1*time.Hour, 2.0, 1*time.Hour, WithJitter(false))
+		ctx, cancel := context.WithCancel(context.Background())
+		tk := b.Ticker(ctx)
+		defer tk.Stop()
+
+		cancel()
+		if _, ok := <-tk.C; ok {
+			t.Error("received a tick after cancel, want closed channel")
+		}
+	})
+
+	t.Run("closes when attempts are exhausted", func(t *testing.T) {
+		b := New(1*time.Millisecond, 1.0, 1*time.Millisecond, WithJitter(false), WithMaxAttempts(3))
+		tk := b.Ticker(context.Background())
+		defer tk.Stop()
+
+		n := 0
+		for range tk.C {
+			n++
+		}
+		if n != 3 {
+			t.Errorf("Ticker() delivered %d ticks, want 3", n)
+		}
+	})
+}